@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/weblfe/gotype/run"
+)
+
+// benchCmd 测量当前主机上解析命令的耗时, 用于比较不同后端
+var benchCmd = &cobra.Command{
+	Use:   "bench [command]",
+	Short: "Benchmarks resolution latency of a command on the current host",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			iterations, _  = cmd.Flags().GetInt(`iterations`)
+			concurrency, _ = cmd.Flags().GetInt(`concurrency`)
+			shell, _       = cmd.Flags().GetString(`shell`)
+			runner         = run.NewRunner(os.Stderr, os.Stdin, os.Stdout).Bind(bin).WithShell(shell)
+			stats          = runner.Bench(args[0], iterations, concurrency)
+		)
+		if v, err := cmd.Flags().GetBool(`json`); err == nil && v {
+			return json.NewEncoder(os.Stdout).Encode(stats)
+		}
+		fmt.Printf("command:     %s\n", stats.Command)
+		fmt.Printf("iterations:  %d (concurrency %d, errors %d)\n", stats.Iterations, stats.Concurrency, stats.Errors)
+		fmt.Printf("latency:     min %s, max %s, mean %s, p50 %s, p99 %s\n", stats.Min, stats.Max, stats.Mean, stats.P50, stats.P99)
+		fmt.Printf("throughput:  %.2f/s over %s\n", stats.Throughput, stats.Total)
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().IntP("iterations", "n", 100, `Number of resolutions`)
+	benchCmd.Flags().IntP("concurrency", "c", 1, `Number of concurrent resolutions`)
+	benchCmd.Flags().String("shell", "", `Resolve with the builtin type of the given shell instead of the type executable`)
+	benchCmd.Flags().Bool("json", false, `Output as JSON`)
+	rootCmd.AddCommand(benchCmd)
+}
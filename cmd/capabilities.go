@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/weblfe/gotype/run"
+)
+
+// capabilitiesCmd 输出当前构建支持的后端、输出格式与平台特性
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Lists the backends, output formats and features supported by this build",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var report = run.ReportCapabilities()
+		if v, err := cmd.Flags().GetBool(`json`); err == nil && v {
+			var encoder = json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.SetEscapeHTML(false)
+			return encoder.Encode(report)
+		}
+		fmt.Printf("os: %s/%s\n", report.OS, report.Arch)
+		for _, items := range [][]run.Capability{report.Backends, report.Formats, report.Features} {
+			for _, v := range items {
+				fmt.Printf("%-8s %-16s %s\n", v.Kind, v.Name, v.Description)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	capabilitiesCmd.Flags().Bool("json", false, `Output as JSON`)
+	rootCmd.AddCommand(capabilitiesCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/weblfe/gotype/run"
+)
+
+type commanderFunc func(command *exec.Cmd) *run.Capture
+
+func (fn commanderFunc) Run(command *exec.Cmd) *run.Capture {
+	return fn(command)
+}
+
+func TestExitCode(t *testing.T) {
+	var runner = run.NewRunner(nil, nil, nil).WithCommander(commanderFunc(func(command *exec.Cmd) *run.Capture {
+		if command.Args[len(command.Args)-1] == "nope" {
+			return &run.Capture{Code: 1}
+		}
+		return &run.Capture{Stdout: []byte("ls is /bin/ls\n")}
+	}))
+	var cases = []struct {
+		names []string
+		want  int
+	}{
+		{[]string{"ls"}, 0},
+		{[]string{"ls", "nope"}, 1},
+		{[]string{"nope"}, 1},
+		{nil, 0},
+	}
+	for _, c := range cases {
+		var results []*run.Result
+		for _, name := range c.names {
+			results = append(results, runner.Resolve(name))
+		}
+		if got := exitCode(results); got != c.want {
+			t.Errorf("exitCode(%q) = %d, want %d", c.names, got, c.want)
+		}
+	}
+}
@@ -2,12 +2,17 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/weblfe/gotype/run"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -15,32 +20,241 @@ var (
 	bin     string
 )
 
+const (
+	formatText = `text`
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "gotype",
 	Short: "Displays the type of the specified command",
 	Long:  `Using the type command,you can view the type of a specified command and determine whether the command is an internal command or an external command.`,
+	Args:  cobra.ArbitraryArgs,
 	// Uncomment the following line if your bare application
 	// has an action associated with it: args 是除 command options 以外的命令的不定参数
 	Run: func(cmd *cobra.Command, args []string) {
 		var (
-			runner = run.NewRunner(os.Stdin, os.Stderr, os.Stdout).Bind(bin)
+			runner = run.NewRunner(os.Stderr, os.Stdin, os.Stdout).Bind(bin)
 		)
-		if v, err := cmd.Flags().GetString(`path`); err == nil && v != "" {
-			runner.Exec(`path`, v)
-			return
+		if v, err := cmd.Flags().GetString(`shell`); err == nil && v != "" {
+			runner.WithShell(v)
+		}
+		if v, err := cmd.Flags().GetString(`rcfile`); err == nil && v != "" {
+			runner.WithRcFile(v)
+		}
+		if v, err := cmd.Flags().GetInt(`max-candidates`); err == nil {
+			runner.WithCandidateThreshold(v)
+		}
+		if v, err := cmd.Flags().GetBool(`no-function`); err == nil {
+			runner.WithoutFunctions(v)
+		}
+		if v, err := cmd.Flags().GetBool(`check-hash`); err == nil {
+			runner.WithHashCheck(v)
+		}
+		if v, err := cmd.Flags().GetStringSlice(`resolver`); err == nil {
+			for _, name := range v {
+				var resolver, err = run.NewPluginResolver(name)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				runner.WithResolver(resolver)
+			}
+		}
+		flag, names, err := collect(cmd, args, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if v, err := cmd.Flags().GetBool(`strip-wrapper`); err == nil && v {
+			names = stripWrappers(runner, names)
+		}
+		if v, err := cmd.Flags().GetBool(`normalize-path`); err == nil && v {
+			normalizePath(runner, names)
+		}
+		if native, err := cmd.Flags().GetBool(`native`); err == nil && native {
+			os.Exit(execNative(runner, flag, names))
+		}
+		mode, err := uniqueMode(cmd, args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if v, err := cmd.Flags().GetBool(`command-v`); err == nil && v {
+			os.Exit(execCommandV(runner, names))
 		}
-		if v, err := cmd.Flags().GetString(`all`); err == nil && v != "" {
-			runner.Exec(`all`, v)
-			return
+		if v, err := cmd.Flags().GetBool(`package-owner`); err == nil && v {
+			os.Exit(execFileInfo(names, func(name string) (string, error) {
+				return runner.PackageOwner(runner.Resolve(name))
+			}))
 		}
-		if v, err := cmd.Flags().GetString(`type`); err == nil && v != "" {
-			runner.Exec(`type`, v)
-			return
+		if v, err := cmd.Flags().GetBool(`arch`); err == nil && v {
+			os.Exit(execFileInfo(names, func(name string) (string, error) {
+				var archs, err = runner.Arch(runner.Resolve(name))
+				return strings.Join(archs, ` `), err
+			}))
 		}
+		var format, _ = cmd.Flags().GetString(`format`)
+		var envFormat, isEnv = run.ParseEnvFormat(format)
+		if format != formatText && !isEnv {
+			fmt.Fprintln(os.Stderr, `format undefined: `+format)
+			os.Exit(1)
+		}
+		if isEnv {
+			if mode != run.UniqueNone {
+				names = run.Unique(names)
+			}
+			os.Exit(exitCode(runner.EnvFile(names, envFormat)))
+		}
+		if flag == "" && len(names) > 0 {
+			flag = `all`
+		}
+		os.Exit(exitCode(runner.ExecBatch(flag, names, mode)))
 	},
 }
 
+// collect 取出选项及待解析的命令列表: 选项值在前, 其后为位置参数, "-" (含选项值) 表示从 stdin 按行读取
+func collect(cmd *cobra.Command, args []string, stdin io.Reader) (string, []string, error) {
+	var (
+		flag  string
+		items []string
+		names []string
+	)
+	for _, name := range []string{`path`, `force-path`, `all`, `type`} {
+		if v, err := cmd.Flags().GetString(name); err == nil && v != "" {
+			flag = name
+			items = append(items, v)
+			break
+		}
+	}
+	for _, v := range append(items, args...) {
+		if v != "-" {
+			names = append(names, v)
+			continue
+		}
+		var scanner = bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				names = append(names, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return flag, names, err
+		}
+	}
+	return flag, names, nil
+}
+
+// uniqueMode 解析 --unique; 模式须以 "=" 连写, "--unique keep-positions" 中的模式会被当作命令名, 此时报错
+func uniqueMode(cmd *cobra.Command, args []string) (run.UniqueMode, error) {
+	var v, err = cmd.Flags().GetString(`unique`)
+	if err != nil {
+		return run.UniqueNone, err
+	}
+	mode, err := run.ParseUniqueMode(v)
+	if err != nil || !cmd.Flags().Changed(`unique`) || v != cmd.Flags().Lookup(`unique`).NoOptDefVal {
+		return mode, err
+	}
+	for _, arg := range args {
+		if _, err := run.ParseUniqueMode(arg); err == nil && arg != "" {
+			return mode, errors.New(`--unique takes its mode with "=", e.g. --unique=` + arg)
+		}
+	}
+	return mode, nil
+}
+
+// stripWrappers 去除 sudo/doas/env 等包装命令, 并在标准错误中注明
+func stripWrappers(runner *run.Runner, names []string) []string {
+	var items = make([]string, 0, len(names))
+	for _, v := range names {
+		var name, wrappers = runner.StripWrapper(v)
+		if len(wrappers) > 0 {
+			fmt.Fprintf(os.Stderr, "note: %s: stripped wrapper %s\n", name, strings.Join(wrappers, ` `))
+		}
+		if name != "" {
+			items = append(items, name)
+		}
+	}
+	return items
+}
+
+// normalizePath 以规范化后的 PATH 解析, 报告被改写的目录项及解析结果的变化
+func normalizePath(runner *run.Runner, names []string) {
+	var (
+		raw           = os.Getenv(`PATH`)
+		path, changes = run.NormalizePath(raw)
+	)
+	for _, v := range changes {
+		if v.Result == "" {
+			fmt.Fprintf(os.Stderr, "WARN: PATH entry %q removed: %s\n", v.Entry, v.Reason)
+		} else {
+			fmt.Fprintf(os.Stderr, "WARN: PATH entry %q -> %s: %s\n", v.Entry, v.Result, v.Reason)
+		}
+	}
+	for _, name := range names {
+		if before, after := run.LookPath(name, raw), run.LookPath(name, path); before != after {
+			fmt.Fprintf(os.Stderr, "note: %s: resolves to %s with the raw PATH, %s with the normalized PATH\n", name, describePath(before), describePath(after))
+		}
+	}
+	runner.WithPathEnv(path)
+}
+
+// describePath 引号包裹的路径, 未找到时为 not found
+func describePath(path string) string {
+	if path == "" {
+		return `not found`
+	}
+	return strconv.Quote(path)
+}
+
+// execCommandV 按 command -v 的语义输出, 与 bash 一致仅在全部未找到时返回 1
+func execCommandV(runner *run.Runner, names []string) int {
+	var code = 1
+	for _, name := range names {
+		if line := runner.Resolve(name).CommandV(); line != "" {
+			fmt.Println(line)
+			code = 0
+		}
+	}
+	return code
+}
+
+// execFileInfo 逐个输出解析出的文件的附加信息(所属软件包、CPU 架构), 任一失败时返回 1
+func execFileInfo(names []string, info func(name string) (string, error)) int {
+	var code int
+	for _, name := range names {
+		var v, err = info(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, name+`: `+err.Error())
+			code = 1
+			continue
+		}
+		fmt.Println(v)
+	}
+	return code
+}
+
+// exitCode 与 type 一致, 任一命令未找到或出错时返回 1
+func exitCode(results []*run.Result) int {
+	for _, rs := range results {
+		if rs.HasErr() || rs.ExitCode() != 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// execNative 透传原生 type 输出, 返回原生退出码
+func execNative(runner *run.Runner, flag string, names []string) int {
+	var code int
+	for _, name := range names {
+		if rs := runner.Native(flag, name); rs.ExitCode() != 0 {
+			code = rs.ExitCode()
+		}
+	}
+	return code
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -59,9 +273,30 @@ func init() {
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
-	rootCmd.Flags().StringP("type", "t", ``, `Output "file", "alias", or "builtin" to indicate that the given instruction is "external instruction", "command alias", or "internal instruction", respectively`)
-	rootCmd.Flags().StringP("path", "p", ``, `If the given instruction is an external instruction, its absolute path is displayed.`)
-	rootCmd.Flags().StringP("all", "a", ``, `Displays information about the given command, including the command alias, in the PATH specified by the environment variable "PATH".`)
+	initFlags(rootCmd)
+}
+
+// initFlags 定义根命令的选项
+func initFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("type", "t", ``, `Output "file", "alias", or "builtin" to indicate that the given instruction is "external instruction", "command alias", or "internal instruction", respectively`)
+	cmd.Flags().StringP("path", "p", ``, `If the given instruction is an external instruction, its absolute path is displayed.`)
+	cmd.Flags().Bool("native", false, `Pass through the exact output of the underlying type command, only the exit code is computed by gotype.`)
+	cmd.Flags().String("shell", "", `Resolve with the builtin type of the given shell (bash, fish, ...) instead of the type executable.`)
+	cmd.Flags().String("rcfile", "", `Source the given rc file in an interactive bash before resolving, so aliases defined there are visible.`)
+	cmd.Flags().String("unique", "", `Deduplicate the command list before resolving: "first" keeps first-seen order, "keep-positions" expands results back to the original positions. The mode must be given with "=", e.g. --unique=keep-positions.`)
+	cmd.Flags().Lookup("unique").NoOptDefVal = string(run.UniqueFirst)
+	cmd.Flags().Bool("strip-wrapper", false, `Strip a leading privilege or environment wrapper (sudo, doas, env) and resolve the wrapped command.`)
+	cmd.Flags().Int("max-candidates", 0, `Warn when type -a returns more candidates than this for a command, 0 disables the warning.`)
+	cmd.Flags().String("format", formatText, `Output format: "text"; "env-file" for systemd EnvironmentFile or "docker-env-file" for docker --env-file, as COMMAND_PATH=/resolved/path lines (non-file commands and colliding keys are skipped with a comment).`)
+	cmd.Flags().Bool("check-hash", false, `Warn when the path hashed by the inspected shell (type -P) differs from a fresh PATH scan. The caller's own hash table is not visible to the subprocess.`)
+	cmd.Flags().StringSlice("resolver", nil, `Try the external gotype-resolver-<name> plugin on PATH before type, may be repeated.`)
+	cmd.Flags().Bool("normalize-path", false, `Canonicalize PATH (drop empty entries, resolve ".", ".." and symlinked directories) before resolving, and report what changed.`)
+	cmd.Flags().Bool("command-v", false, `Behave like "command -v": print the path for files, the definition for aliases, the name for builtins and functions, nothing when not found, exit 1 when none is found.`)
+	cmd.Flags().Bool("package-owner", false, `Print the system package owning the resolved file (dpkg or rpm, linux only).`)
+	cmd.Flags().Bool("arch", false, `Print the CPU architectures of the resolved Mach-O file, several for universal binaries (darwin only).`)
+	cmd.Flags().StringP("force-path", "P", ``, `Search PATH for the given command even if it is an alias, builtin, or function, and display its absolute path.`)
+	cmd.Flags().BoolP("no-function", "f", false, `Suppress shell function lookup, like type -f.`)
+	cmd.Flags().StringP("all", "a", ``, `Displays information about the given command, including the command alias, in the PATH specified by the environment variable "PATH".`)
 }
 
 // initConfig reads in config file and ENV variables if set.
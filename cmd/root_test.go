@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/weblfe/gotype/run"
+)
+
+func parse(t *testing.T, args ...string) (*cobra.Command, []string) {
+	t.Helper()
+	var cmd = &cobra.Command{}
+	initFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd, cmd.Flags().Args()
+}
+
+func TestCollectReadsStdinForFlagValue(t *testing.T) {
+	var cmd, args = parse(t, "-t", "-", "cd")
+	var flag, names, err = collect(cmd, args, strings.NewReader("ls\n\n  grep \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flag != "type" || strings.Join(names, ",") != "ls,grep,cd" {
+		t.Errorf("collect = %q, %q, want type, [ls grep cd]", flag, names)
+	}
+}
+
+func TestCollectReadsStdinForPositional(t *testing.T) {
+	var cmd, args = parse(t, "-p", "ls", "-", "cd")
+	var flag, names, err = collect(cmd, args, strings.NewReader("grep\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flag != "path" || strings.Join(names, ",") != "ls,grep,cd" {
+		t.Errorf("collect = %q, %q, want path, [ls grep cd]", flag, names)
+	}
+}
+
+func TestUniqueMode(t *testing.T) {
+	var cases = []struct {
+		args []string
+		want run.UniqueMode
+		err  bool
+	}{
+		{[]string{"ls"}, run.UniqueNone, false},
+		{[]string{"--unique", "ls"}, run.UniqueFirst, false},
+		{[]string{"--unique=keep-positions", "ls"}, run.UniqueKeepPositions, false},
+		{[]string{"--unique", "keep-positions", "ls"}, run.UniqueFirst, true},
+		{[]string{"--unique=bogus", "ls"}, run.UniqueNone, true},
+	}
+	for _, c := range cases {
+		var cmd, args = parse(t, c.args...)
+		var mode, err = uniqueMode(cmd, args)
+		if (err != nil) != c.err || (err == nil && mode != c.want) {
+			t.Errorf("uniqueMode(%q) = %q, %v; want %q, error %v", c.args, mode, err, c.want, c.err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package compat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 对比 bash 内建 type 与 gotype 解析模式(-t/-p/-a/-P/-f)的 stdout/stderr/退出码, 需设置 GOTYPE_COMPAT=1 开启
+// 有意对比默认解析模式而非单独的 --compat 模式; 有意保留的差异记录在 allowlist 中, 其余差异均视为失败
+
+const (
+	envEnable = `GOTYPE_COMPAT`
+	fixture   = `shopt -s expand_aliases
+alias gotype-compat-alias='ls -l'
+gotype_compat_fn() {
+	echo compat
+}
+`
+)
+
+type (
+	compatCase struct {
+		flags []string
+		label string // 用例类别, 用于 allowlist
+		name  string
+	}
+
+	outcome struct {
+		stdout []byte
+		stderr []byte
+		code   int
+	}
+)
+
+var (
+	modes = [][]string{
+		{`-t`}, {`-p`}, {`-a`}, {`-P`},
+		{`-f`, `-t`}, {`-f`, `-p`}, {`-f`, `-a`},
+	}
+	names = []struct {
+		label string
+		name  string
+	}{
+		{`file`, `gotype-compat-plain`},
+		{`space`, `gotype compat space`},
+		{`quote`, `gotype'compat'quote`},
+		{`alias`, `gotype-compat-alias`},
+		{`function`, `gotype_compat_fn`},
+		{`builtin`, `cd`},
+		{`keyword`, `if`},
+		{`missing`, `gotype-compat-missing`},
+	}
+)
+
+func TestCompatWithBash(t *testing.T) {
+	if os.Getenv(envEnable) == "" {
+		t.Skip(`set ` + envEnable + `=1 to compare with bash type`)
+	}
+	var bash, err = exec.LookPath(`bash`)
+	if err != nil {
+		t.Skip(`bash not found`)
+	}
+	var (
+		work   = t.TempDir()
+		gotype = filepath.Join(work, `gotype`)
+		rc     = filepath.Join(work, `fixture.sh`)
+		bin    = filepath.Join(work, `bin`)
+	)
+	if out, err := exec.Command(`go`, `build`, `-o`, gotype, `github.com/weblfe/gotype`).CombinedOutput(); err != nil {
+		t.Fatalf("build gotype: %v\n%s", err, out)
+	}
+	if err = os.WriteFile(rc, []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Mkdir(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range names[:3] {
+		if err = os.WriteFile(filepath.Join(bin, v.name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		allowed = loadAllowlist(t)
+		env     = append(os.Environ(), `PATH=`+bin+string(filepath.ListSeparator)+os.Getenv(`PATH`))
+	)
+	for _, c := range cases() {
+		var id = strings.Join(c.flags, ` `) + ` ` + c.label
+		t.Run(id, func(t *testing.T) {
+			var want = run(env, bash, append(append([]string{`-c`, `. "$1"; shift; type "$@"`, `bash`, rc}, c.flags...), c.name)...)
+			var got = run(env, gotype, append(append([]string{`--shell`, bash, `--rcfile`, rc}, c.flags...), c.name)...)
+			for _, v := range []struct {
+				stream    string
+				want, got []byte
+			}{
+				{`stdout`, want.stdout, got.stdout},
+				{`stderr`, want.stderr, got.stderr},
+			} {
+				if !bytes.Equal(v.want, v.got) && !allowed[id+`|`+v.stream] {
+					t.Errorf("%s differs for %q:\nbash:   %q\ngotype: %q", v.stream, c.name, v.want, v.got)
+				}
+			}
+			if want.code != got.code && !allowed[id+`|code`] {
+				t.Errorf("exit code differs for %q: bash %d, gotype %d", c.name, want.code, got.code)
+			}
+		})
+	}
+}
+
+func cases() []compatCase {
+	var items []compatCase
+	for _, flags := range modes {
+		for _, v := range names {
+			items = append(items, compatCase{flags: flags, label: v.label, name: v.name})
+		}
+	}
+	return items
+}
+
+func run(env []string, bin string, args ...string) outcome {
+	var (
+		command        = exec.Command(bin, args...)
+		stdout, stderr bytes.Buffer
+	)
+	command.Env = env
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	var code int
+	if err := command.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		} else {
+			code = -1
+			fmt.Fprintln(&stderr, err)
+		}
+	}
+	return outcome{stdout: stdout.Bytes(), stderr: stderr.Bytes(), code: code}
+}
+
+func loadAllowlist(t *testing.T) map[string]bool {
+	var file, err = os.Open(`allowlist`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var (
+		items   = map[string]bool{}
+		scanner = bufio.NewScanner(file)
+	)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, `#`) {
+			items[line] = true
+		}
+	}
+	return items
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "applet", Kind: CapabilityFeature, Description: "detects busybox/toybox applet symlinks"})
+}
+
+var (
+	// 多路复用程序, 按软链目标的文件名识别
+	multiplexers = []string{`busybox`, `toybox`}
+)
+
+// Applet 解析出的文件是 busybox/toybox 的软链时, 返回多路复用程序的真实路径
+func (rs *Result) Applet() string {
+	var path = rs.Path()
+	if path == "" {
+		return ``
+	}
+	return appletOf(path)
+}
+
+// IsApplet 解析出的文件是否为 busybox/toybox 的 applet
+func (rs *Result) IsApplet() bool {
+	return rs.Applet() != ""
+}
+
+func appletOf(path string) string {
+	var target, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return ``
+	}
+	var base = filepath.Base(target)
+	// 直接调用多路复用程序本身不算 applet
+	if base == filepath.Base(path) {
+		return ``
+	}
+	for _, v := range multiplexers {
+		if base == v || strings.HasPrefix(base, v+`.`) {
+			return target
+		}
+	}
+	return ``
+}
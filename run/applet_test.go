@@ -0,0 +1,57 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplet(t *testing.T) {
+	var dir = t.TempDir()
+	for _, name := range []string{"busybox", "toybox.static", "grep"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var links = map[string]string{
+		"ls":    "busybox",
+		"cat":   "toybox.static",
+		"egrep": "grep",
+		"sh":    "ls", // 链式软链最终指向 busybox
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Skip("symlinks unsupported:", err)
+		}
+	}
+	var (
+		busybox = filepath.Join(dir, "busybox")
+		toybox  = filepath.Join(dir, "toybox.static")
+	)
+	if v, err := filepath.EvalSymlinks(busybox); err == nil {
+		busybox = v
+	}
+	if v, err := filepath.EvalSymlinks(toybox); err == nil {
+		toybox = v
+	}
+	var cases = []struct {
+		name string
+		want string
+	}{
+		{"ls", busybox},
+		{"sh", busybox},
+		{"cat", toybox},
+		{"busybox", ""},
+		{"egrep", ""},
+		{"grep", ""},
+		{"missing", ""},
+	}
+	for _, c := range cases {
+		var path = filepath.Join(dir, c.name)
+		var runner, _ = newTestRunner(t, capturing(c.name+" is "+path+"\n", "", 0, nil))
+		var rs = runner.Resolve(c.name)
+		if rs.Applet() != c.want || rs.IsApplet() != (c.want != "") {
+			t.Errorf("%s: Applet() = %q, IsApplet() = %v, want %q", c.name, rs.Applet(), rs.IsApplet(), c.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package run
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFile = errors.New(`command does not resolve to a file`)
+)
+
+// Arch 解析出的文件包含的 CPU 架构, 通用二进制返回多个, 仅 darwin 支持(Mach-O)
+func (r *Runner) Arch(rs *Result) ([]string, error) {
+	var path = rs.Path()
+	if path == "" {
+		return nil, ErrNotFile
+	}
+	return archOf(path)
+}
@@ -0,0 +1,45 @@
+package run
+
+import (
+	"debug/macho"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "arch", Kind: CapabilityFeature, Description: "CPU architectures of a resolved Mach-O file, including universal binaries", Platform: "darwin"})
+}
+
+var (
+	// 与 lipo -archs 一致的架构名
+	machoArchs = map[macho.Cpu]string{
+		macho.Cpu386:   `i386`,
+		macho.CpuAmd64: `x86_64`,
+		macho.CpuArm:   `arm`,
+		macho.CpuArm64: `arm64`,
+		macho.CpuPpc:   `ppc`,
+		macho.CpuPpc64: `ppc64`,
+	}
+)
+
+func archOf(path string) ([]string, error) {
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		var archs = make([]string, 0, len(fat.Arches))
+		for _, v := range fat.Arches {
+			archs = append(archs, machoArch(v.Cpu))
+		}
+		return archs, nil
+	}
+	var file, err = macho.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return []string{machoArch(file.Cpu)}, nil
+}
+
+func machoArch(cpu macho.Cpu) string {
+	if v, ok := machoArchs[cpu]; ok {
+		return v
+	}
+	return cpu.String()
+}
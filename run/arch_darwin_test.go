@@ -0,0 +1,23 @@
+package run
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestArchOfTestBinary(t *testing.T) {
+	var path, err = os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = map[string]string{"amd64": "x86_64", "arm64": "arm64"}[runtime.GOARCH]
+	var runner, _ = newTestRunner(t, capturing("gotype-test is "+path+"\n", "", 0, nil))
+	archs, err := runner.Arch(runner.Resolve("gotype-test"))
+	if err != nil || len(archs) != 1 || archs[0] != want {
+		t.Errorf("archs = %q, %v, want [%s]", archs, err, want)
+	}
+	if _, err = runner.Arch(NewResult()); err != ErrNotFile {
+		t.Errorf("err = %v, want ErrNotFile", err)
+	}
+}
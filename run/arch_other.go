@@ -0,0 +1,13 @@
+//go:build !darwin
+// +build !darwin
+
+package run
+
+import (
+	"errors"
+	"runtime"
+)
+
+func archOf(string) ([]string, error) {
+	return nil, errors.New(`architecture detection is not supported on ` + runtime.GOOS)
+}
@@ -0,0 +1,72 @@
+package run
+
+import (
+	"errors"
+)
+
+type UniqueMode string
+
+const (
+	UniqueNone          UniqueMode = ""               // 不去重
+	UniqueFirst         UniqueMode = "first"          // 去重, 按首次出现顺序输出
+	UniqueKeepPositions UniqueMode = "keep-positions" // 去重解析, 结果按原输入位置展开输出
+)
+
+// ParseUniqueMode 解析 --unique 的取值
+func ParseUniqueMode(v string) (UniqueMode, error) {
+	switch mode := UniqueMode(v); mode {
+	case UniqueNone, UniqueFirst, UniqueKeepPositions:
+		return mode, nil
+	}
+	return UniqueNone, errors.New(`unique mode undefined: ` + v)
+}
+
+// Unique 去除重复命令, 保留首次出现的顺序
+func Unique(cmds []string) []string {
+	var (
+		seen  = make(map[string]bool, len(cmds))
+		items = make([]string, 0, len(cmds))
+	)
+	for _, v := range cmds {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		items = append(items, v)
+	}
+	return items
+}
+
+// ExecBatch 批量解析并输出, 返回与输出顺序一致的结果
+func (r *Runner) ExecBatch(flag string, cmds []string, mode UniqueMode) []*Result {
+	var results []*Result
+	if err := r.check(); err != nil {
+		var rs = NewResult()
+		rs.err = err
+		r.errLog("cmd err:", rs.err)
+		return append(results, rs)
+	}
+	if mode == UniqueNone {
+		for _, v := range cmds {
+			results = append(results, r.Exec(flag, v))
+		}
+		return results
+	}
+	var resolved = make(map[string]*Result, len(cmds))
+	for _, v := range Unique(cmds) {
+		if v != "" {
+			resolved[v] = r.lookup(flag, v)
+		}
+	}
+	var order = Unique(cmds)
+	if mode == UniqueKeepPositions {
+		order = cmds
+	}
+	for _, v := range order {
+		if rs, ok := resolved[v]; ok {
+			r.emit(rs)
+			results = append(results, rs)
+		}
+	}
+	return results
+}
@@ -0,0 +1,65 @@
+package run
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	var got = Unique([]string{"ls", "cd", "ls", "grep", "cd"})
+	if strings.Join(got, ",") != "ls,cd,grep" {
+		t.Errorf("Unique = %q, want [ls cd grep]", got)
+	}
+}
+
+var batchOutputs = map[string]string{
+	"ls": "ls is /bin/ls\n",
+	"cd": "cd is /bin/cd\n",
+}
+
+func TestExecBatchUniqueFirst(t *testing.T) {
+	var (
+		calls       = map[string]int{}
+		runner, out = newTestRunner(t, canned(batchOutputs, calls))
+		results     = runner.ExecBatch("path", []string{"ls", "cd", "ls", "nope", "cd"}, UniqueFirst)
+		stdout, _   = out()
+	)
+	if string(stdout) != "/bin/ls\n/bin/cd\nnope not found\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if len(results) != 3 || calls["ls"] != 1 || calls["cd"] != 1 {
+		t.Errorf("results = %d, calls = %v, want 3 results and one call per command", len(results), calls)
+	}
+}
+
+func TestExecBatchKeepPositions(t *testing.T) {
+	var (
+		calls       = map[string]int{}
+		runner, out = newTestRunner(t, canned(batchOutputs, calls))
+		results     = runner.ExecBatch("path", []string{"ls", "cd", "ls", "nope", "cd"}, UniqueKeepPositions)
+		stdout, _   = out()
+	)
+	if string(stdout) != "/bin/ls\n/bin/cd\n/bin/ls\nnope not found\n/bin/cd\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if len(results) != 5 || calls["ls"] != 1 || calls["cd"] != 1 {
+		t.Errorf("results = %d, calls = %v, want 5 results and one call per command", len(results), calls)
+	}
+	if results[0] != results[2] {
+		t.Error("repeated positions should share the single resolution")
+	}
+}
+
+func TestExecBatchUniqueLogsErrors(t *testing.T) {
+	var (
+		runner, out = newTestRunner(t, canned(batchOutputs, nil))
+		results     = runner.ExecBatch("bogus", []string{"ls", "ls"}, UniqueFirst)
+		_, stderr   = out()
+	)
+	if len(results) != 1 || !results[0].HasErr() {
+		t.Fatalf("results = %v, want one error result", results)
+	}
+	if !strings.Contains(string(stderr), "cmd err:") {
+		t.Errorf("stderr = %q, want the error logged", stderr)
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// BenchStats 重复解析同一命令的耗时统计
+	BenchStats struct {
+		Command     string        `json:"command"`
+		Iterations  int           `json:"iterations"`
+		Concurrency int           `json:"concurrency"`
+		Errors      int           `json:"errors"` // 执行失败或以非零码退出(如命令不存在)的次数
+		Total       time.Duration `json:"total_ns"`
+		Min         time.Duration `json:"min_ns"`
+		Max         time.Duration `json:"max_ns"`
+		Mean        time.Duration `json:"mean_ns"`
+		P50         time.Duration `json:"p50_ns"`
+		P99         time.Duration `json:"p99_ns"`
+		Throughput  float64       `json:"throughput"` // 每秒解析次数
+	}
+)
+
+// Bench 以 concurrency 个协程共解析 cmd iterations 次, 绕过缓存以测量真实的后端耗时
+func (r *Runner) Bench(cmd string, iterations, concurrency int) *BenchStats {
+	if iterations < 1 {
+		iterations = 1
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > iterations {
+		concurrency = iterations
+	}
+	var (
+		stats     = &BenchStats{Command: cmd, Iterations: iterations, Concurrency: concurrency}
+		durations = make([]time.Duration, iterations)
+		failed    = make([]bool, iterations)
+		jobs      = make(chan int)
+		wg        sync.WaitGroup
+		start     = time.Now()
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				var begin = time.Now()
+				var rs = r.resolve(cmd)
+				durations[n] = time.Since(begin)
+				failed[n] = rs.failed()
+			}
+		}()
+	}
+	for i := 0; i < iterations; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	stats.Total = time.Since(start)
+	stats.collect(durations, failed)
+	return stats
+}
+
+func (stats *BenchStats) collect(durations []time.Duration, failed []bool) {
+	var sum time.Duration
+	for i, v := range durations {
+		sum += v
+		if failed[i] {
+			stats.Errors++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	stats.Min = durations[0]
+	stats.Max = durations[len(durations)-1]
+	stats.Mean = sum / time.Duration(len(durations))
+	stats.P50 = percentile(durations, 0.50)
+	stats.P99 = percentile(durations, 0.99)
+	if stats.Total > 0 {
+		stats.Throughput = float64(len(durations)) / stats.Total.Seconds()
+	}
+}
+
+// percentile 最近秩法取已排序耗时的分位数
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	var rank = int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,60 @@
+package run
+
+import (
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBench(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, commanderFunc(func(*exec.Cmd) *Capture {
+		var n = atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond)
+		if n%4 == 0 {
+			return &Capture{Stderr: []byte("ls: not found\n"), Code: 1}
+		}
+		return &Capture{Stdout: []byte("ls is /bin/ls\n")}
+	}))
+	var stats = runner.Bench("ls", 20, 4)
+	if calls != 20 {
+		t.Errorf("calls = %d, want every iteration to bypass the cache", calls)
+	}
+	if stats.Command != "ls" || stats.Iterations != 20 || stats.Concurrency != 4 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.Errors != 5 {
+		t.Errorf("errors = %d, want nonzero exit codes counted", stats.Errors)
+	}
+	if stats.Min < time.Millisecond || stats.Min > stats.P50 || stats.P50 > stats.P99 || stats.P99 > stats.Max {
+		t.Errorf("durations out of order: min %v p50 %v p99 %v max %v", stats.Min, stats.P50, stats.P99, stats.Max)
+	}
+	if stats.Mean < stats.Min || stats.Mean > stats.Max || stats.Total <= 0 || stats.Throughput <= 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBenchClampsArguments(t *testing.T) {
+	var runner, _ = newTestRunner(t, capturing("ls is /bin/ls\n", "", 0, nil))
+	var stats = runner.Bench("ls", 0, 8)
+	if stats.Iterations != 1 || stats.Concurrency != 1 || stats.Errors != 0 {
+		t.Errorf("stats = %+v, want one iteration on one worker", stats)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted = make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	if v := percentile(sorted, 0.50); v != 50 {
+		t.Errorf("p50 = %d, want 50", v)
+	}
+	if v := percentile(sorted, 0.99); v != 99 {
+		t.Errorf("p99 = %d, want 99", v)
+	}
+	if v := percentile(sorted[:1], 0.99); v != 1 {
+		t.Errorf("p99 of one = %d, want 1", v)
+	}
+}
@@ -0,0 +1,84 @@
+package run
+
+import (
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "cache", Kind: CapabilityFeature, Description: "per-result cache with TTL and policy hook"})
+}
+
+const (
+	defaultCacheTTL = time.Minute // 默认缓存时长, 长期运行的进程中新安装的命令最多延迟这么久可见
+)
+
+type (
+	// CachePolicy 决定结果是否缓存以及缓存时长
+	CachePolicy func(rs *Result) (cache bool, ttl time.Duration)
+
+	cacheEntry struct {
+		result *Result
+		expire time.Time
+	}
+
+	resultCache struct {
+		locker sync.Mutex
+		items  map[string]cacheEntry
+	}
+)
+
+// WithCacheTTL 全局缓存时长, 默认一分钟, <=0 时不缓存
+func (r *Runner) WithCacheTTL(ttl time.Duration) *Runner {
+	r.cacheTTL = ttl
+	r.cache.clear()
+	return r
+}
+
+// WithCachePolicy 按结果定制缓存策略, nil 恢复默认(全部按全局时长缓存)
+func (r *Runner) WithCachePolicy(policy CachePolicy) *Runner {
+	r.cachePolicyFn = policy
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) cachePolicy() CachePolicy {
+	if r.cachePolicyFn != nil {
+		return r.cachePolicyFn
+	}
+	return r.defaultCachePolicy
+}
+
+func (r *Runner) defaultCachePolicy(*Result) (bool, time.Duration) {
+	return r.cacheTTL > 0, r.cacheTTL
+}
+
+func (c *resultCache) get(key string) (*Result, bool) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	var v, ok = c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(v.expire) {
+		delete(c.items, key)
+		return nil, false
+	}
+	return v.result, true
+}
+
+// clear 解析相关的配置变化后, 已缓存的结果不再有效
+func (c *resultCache) clear() {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	c.items = nil
+}
+
+func (c *resultCache) set(key string, rs *Result, ttl time.Duration) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	if c.items == nil {
+		c.items = make(map[string]cacheEntry)
+	}
+	c.items[key] = cacheEntry{result: rs, expire: time.Now().Add(ttl)}
+}
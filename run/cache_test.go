@@ -0,0 +1,79 @@
+package run
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachePolicyDeclinesNotFound(t *testing.T) {
+	var calls = map[string]int{}
+	var runner, _ = newTestRunner(t, canned(map[string]string{"ls": "ls is /bin/ls\n"}, calls))
+	runner.WithCachePolicy(func(rs *Result) (bool, time.Duration) {
+		return rs.Type() != TypeUnFound.String(), time.Minute
+	})
+	for i := 0; i < 3; i++ {
+		runner.Resolve("ls")
+		runner.Resolve("nope")
+	}
+	if calls["ls"] != 1 {
+		t.Errorf("ls resolved %d times, want 1 (cached)", calls["ls"])
+	}
+	if calls["nope"] != 3 {
+		t.Errorf("nope resolved %d times, want 3 (not cached)", calls["nope"])
+	}
+}
+
+func TestCacheDefaultPolicy(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, capturing("ls is /bin/ls\n", "", 0, &calls))
+	runner.Resolve("ls")
+	runner.Resolve("ls")
+	if calls != 1 {
+		t.Errorf("with the default TTL resolved %d times, want 1", calls)
+	}
+	runner.WithCacheTTL(0)
+	runner.Resolve("ls")
+	runner.Resolve("ls")
+	if calls != 3 {
+		t.Errorf("with caching off resolved %d times, want 3", calls)
+	}
+}
+
+func TestCacheClearedOnSettingChange(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, capturing("ls is /bin/ls\n", "", 0, &calls))
+	runner.WithCacheTTL(time.Minute)
+	runner.Resolve("ls")
+	runner.WithShell("bash")
+	runner.Resolve("ls")
+	runner.WithPathEnv("/usr/bin")
+	runner.Resolve("ls")
+	runner.WithRcFile("/dev/null")
+	runner.Resolve("ls")
+	if calls != 4 {
+		t.Errorf("resolved %d times, want 4 (cache cleared on every setting change)", calls)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, capturing("ls is /bin/ls\n", "", 0, &calls))
+	runner.WithCachePolicy(func(*Result) (bool, time.Duration) {
+		return true, time.Millisecond
+	})
+	runner.Resolve("ls")
+	time.Sleep(5 * time.Millisecond)
+	runner.Resolve("ls")
+	if calls != 2 {
+		t.Errorf("resolved %d times, want 2 after expiry", calls)
+	}
+}
+
+func TestCandidateThresholdClearsCache(t *testing.T) {
+	var runner, read = newTestRunner(t, capturing("ls is /usr/bin/ls\nls is /bin/ls\n", "", 0, nil))
+	runner.Resolve("ls")
+	runner.WithCandidateThreshold(1).Resolve("ls")
+	if _, stderr := read(); string(stderr) != "WARN: ls: 2 candidates, more than 1\n" {
+		t.Errorf("stderr = %q, want the warning after the threshold changed", stderr)
+	}
+}
@@ -0,0 +1,28 @@
+package run
+
+import (
+	"strconv"
+)
+
+// CandidateCount type -a 返回的候选条目数, 未找到时为 0
+func (rs *Result) CandidateCount() int {
+	if rs.kind == TypeUnFound {
+		return 0
+	}
+	return len(rs.entries)
+}
+
+// WithCandidateThreshold 候选条目数超过 n 时告警, 用于发现 PATH 污染; n<=0 不告警
+// 告警在解析时给出, 因此修改阈值会清空缓存
+func (r *Runner) WithCandidateThreshold(n int) *Runner {
+	r.candidateThreshold = n
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) warnCandidates(rs *Result) {
+	if r.candidateThreshold <= 0 || rs.CandidateCount() <= r.candidateThreshold {
+		return
+	}
+	r.errLog("WARN:", rs.name+`:`, strconv.Itoa(rs.CandidateCount()), `candidates, more than`, strconv.Itoa(r.candidateThreshold))
+}
@@ -0,0 +1,49 @@
+package run
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCandidateCount(t *testing.T) {
+	var cases = []struct {
+		name   string
+		stdout string
+		code   int
+		want   int
+	}{
+		{"single", "ls is /bin/ls\n", 0, 1},
+		{"multiple", "ls is aliased to `ls --color'\nls is /usr/bin/ls\nls is /bin/ls\n", 0, 3},
+		{"function body", "ls is a function\nls () \n{ \n    command ls -F \"$@\"\n}\nls is /usr/bin/ls\nls is /bin/ls\n", 0, 3},
+		{"not found", "", 1, 0},
+	}
+	for _, c := range cases {
+		var runner, _ = newTestRunner(t, capturing(c.stdout, "", c.code, nil))
+		if n := runner.Resolve("ls").CandidateCount(); n != c.want {
+			t.Errorf("%s: CandidateCount() = %d, want %d", c.name, n, c.want)
+		}
+	}
+}
+
+func TestCandidateThreshold(t *testing.T) {
+	var stdout = "ls is /usr/local/bin/ls\nls is /usr/bin/ls\nls is /bin/ls\n"
+	for _, c := range []struct {
+		threshold int
+		warn      bool
+	}{{0, false}, {3, false}, {4, false}, {2, true}, {1, true}} {
+		var runner, read = newTestRunner(t, capturing(stdout, "", 0, nil))
+		runner.WithCandidateThreshold(c.threshold).Resolve("ls")
+		var _, stderr = read()
+		if !c.warn {
+			if strings.Contains(string(stderr), "WARN:") {
+				t.Errorf("threshold %d: stderr = %q, want no warning", c.threshold, stderr)
+			}
+			continue
+		}
+		var want = "WARN: ls: 3 candidates, more than " + strconv.Itoa(c.threshold) + "\n"
+		if string(stderr) != want {
+			t.Errorf("threshold %d: stderr = %q, want %q", c.threshold, stderr, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type (
+	// Capability 当前构建支持的一项能力
+	Capability struct {
+		Name        string `json:"name"`
+		Kind        string `json:"kind"`
+		Description string `json:"description"`
+		Platform    string `json:"platform,omitempty"` // 为空表示与平台无关
+	}
+
+	// CapabilityReport 按类别汇总的能力清单
+	CapabilityReport struct {
+		OS       string       `json:"os"`
+		Arch     string       `json:"arch"`
+		Backends []Capability `json:"backends"`
+		Formats  []Capability `json:"formats"`
+		Features []Capability `json:"features"`
+	}
+)
+
+const (
+	CapabilityBackend = "backend"
+	CapabilityFormat  = "format"
+	CapabilityFeature = "feature"
+)
+
+var (
+	capabilityLocker sync.RWMutex
+	capabilities     = map[string]Capability{}
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "type-bin", Kind: CapabilityBackend, Description: "external type executable, overridable by " + builtInType})
+	RegisterCapability(Capability{Name: "text", Kind: CapabilityFormat, Description: "human readable output"})
+}
+
+// RegisterCapability 登记能力, 同类同名的重复登记会覆盖之前的描述
+func RegisterCapability(c Capability) {
+	capabilityLocker.Lock()
+	defer capabilityLocker.Unlock()
+	capabilities[c.Kind+"/"+c.Name] = c
+}
+
+// Capabilities 已登记的全部能力, 按类别与名称排序
+func Capabilities() []Capability {
+	capabilityLocker.RLock()
+	defer capabilityLocker.RUnlock()
+	var items = make([]Capability, 0, len(capabilities))
+	for _, v := range capabilities {
+		items = append(items, v)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// HasCapability 是否支持指定能力
+func HasCapability(kind string, name string) bool {
+	capabilityLocker.RLock()
+	defer capabilityLocker.RUnlock()
+	var _, ok = capabilities[kind+"/"+name]
+	return ok
+}
+
+// ReportCapabilities 当前构建与平台的能力清单
+func ReportCapabilities() *CapabilityReport {
+	var report = &CapabilityReport{
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Backends: []Capability{},
+		Formats:  []Capability{},
+		Features: []Capability{},
+	}
+	for _, v := range Capabilities() {
+		switch v.Kind {
+		case CapabilityBackend:
+			report.Backends = append(report.Backends, v)
+		case CapabilityFormat:
+			report.Formats = append(report.Formats, v)
+		default:
+			report.Features = append(report.Features, v)
+		}
+	}
+	return report
+}
@@ -0,0 +1,95 @@
+package run
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCoreCapabilities(t *testing.T) {
+	var report = ReportCapabilities()
+	if report.OS != runtime.GOOS || report.Arch != runtime.GOARCH {
+		t.Errorf("platform = %s/%s, want %s/%s", report.OS, report.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	for _, v := range []struct{ kind, name string }{
+		{CapabilityBackend, "type-bin"},
+		{CapabilityBackend, "shell"},
+		{CapabilityBackend, "fish"},
+		{CapabilityBackend, "plugin"},
+		{CapabilityFormat, "text"},
+		{CapabilityFormat, "env-file"},
+		{CapabilityFormat, "docker-env-file"},
+		{CapabilityFeature, "native"},
+		{CapabilityFeature, "cache"},
+		{CapabilityFeature, "rcfile"},
+	} {
+		if !HasCapability(v.kind, v.name) {
+			t.Errorf("missing %s %s", v.kind, v.name)
+		}
+	}
+	if HasCapability(CapabilityFormat, "native") {
+		t.Error("native is a passthrough mode, not an output format")
+	}
+}
+
+func TestPlatformCapabilities(t *testing.T) {
+	for _, v := range Capabilities() {
+		switch v.Platform {
+		case "":
+		case "unix":
+			if runtime.GOOS == "windows" {
+				t.Errorf("%s is unix only but reported on windows", v.Name)
+			}
+		default:
+			if v.Platform != runtime.GOOS {
+				t.Errorf("%s is %s only but reported on %s", v.Name, v.Platform, runtime.GOOS)
+			}
+		}
+	}
+	if got := HasCapability(CapabilityFeature, "package-owner"); got != (runtime.GOOS == "linux") {
+		t.Errorf("package-owner reported = %v on %s", got, runtime.GOOS)
+	}
+	if got := HasCapability(CapabilityFeature, "arch"); got != (runtime.GOOS == "darwin") {
+		t.Errorf("arch reported = %v on %s", got, runtime.GOOS)
+	}
+}
+
+func TestUnsupportedPlatformFeatures(t *testing.T) {
+	var runner, _ = newTestRunner(t, capturing("ls is /bin/ls\n", "", 0, nil))
+	var rs = runner.Resolve("ls")
+	if runtime.GOOS != "darwin" {
+		if _, err := runner.Arch(rs); err == nil {
+			t.Error("Arch should fail outside darwin")
+		}
+	}
+	if runtime.GOOS != "linux" {
+		if _, err := runner.PackageOwner(rs); err == nil || err == ErrNoPackageOwner || err == ErrNotFile {
+			t.Errorf("PackageOwner err = %v, want unsupported outside linux", err)
+		}
+	}
+}
+
+func TestPackageOwner(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("package owner lookup is linux only")
+	}
+	var runner, _ = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+		var args = strings.Join(append([]string{filepath.Base(command.Args[0])}, command.Args[1:]...), " ")
+		switch args {
+		case "type -a ls":
+			return &Capture{Stdout: []byte("ls is /usr/bin/gotype-test-ls\n")}
+		case "dpkg-query -S /bin/gotype-test-ls":
+			return &Capture{Stdout: []byte("coreutils: /bin/gotype-test-ls\n")}
+		}
+		return &Capture{Code: 1}
+	}))
+	var owner, err = runner.PackageOwner(runner.Resolve("ls"))
+	if err != nil || owner != "coreutils" {
+		t.Errorf("owner = %q, %v, want coreutils", owner, err)
+	}
+	if _, err = runner.PackageOwner(NewResult()); err != ErrNotFile {
+		t.Errorf("err = %v, want ErrNotFile", err)
+	}
+}
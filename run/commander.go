@@ -0,0 +1,55 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+)
+
+type (
+	// Capture 子进程的原始输出与退出码
+	Capture struct {
+		Stdout []byte
+		Stderr []byte
+		Code   int
+		Err    error
+	}
+
+	// Commander 执行底层 type 子进程, 可替换用于模拟输出
+	Commander interface {
+		Run(command *exec.Cmd) *Capture
+	}
+
+	execCommander struct{}
+)
+
+const (
+	codeCannotExec = 127 // 无法执行时沿用 shell 的 command not found 退出码
+)
+
+func (execCommander) Run(command *exec.Cmd) *Capture {
+	var (
+		stdout  bytes.Buffer
+		stderr  bytes.Buffer
+		capture = new(Capture)
+	)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			capture.Code = exitErr.ExitCode()
+		} else {
+			capture.Code = codeCannotExec
+			capture.Err = err
+		}
+	}
+	capture.Stdout = stdout.Bytes()
+	capture.Stderr = stderr.Bytes()
+	return capture
+}
+
+// Failed 子进程未能执行或以非零码退出
+func (c *Capture) Failed() bool {
+	return c.Err != nil || c.Code != 0
+}
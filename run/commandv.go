@@ -0,0 +1,14 @@
+package run
+
+// CommandV 与 POSIX command -v 一致的输出: 外部命令为路径, 别名为 alias 定义, 内建/函数/关键字为命令名, 未找到为空
+func (rs *Result) CommandV() string {
+	switch rs.kind {
+	case TypeFile:
+		return rs.Path()
+	case TypeAlias:
+		return `alias ` + rs.name + `=` + shellQuote(rs.Alias())
+	case TypeBuiltin, TypeFunction, TypeKeyword:
+		return rs.name
+	}
+	return ``
+}
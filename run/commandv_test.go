@@ -0,0 +1,48 @@
+package run
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCommandV(t *testing.T) {
+	var cases = []struct {
+		cmd    string
+		stdout string
+		code   int
+		want   string
+	}{
+		{"ls", "ls is /usr/bin/ls\nls is /bin/ls\n", 0, "/usr/bin/ls"},
+		{"ls", "ls is hashed (/usr/bin/ls)\n", 0, "/usr/bin/ls"},
+		{"ll", "ll is aliased to `ls -l'\n", 0, "alias ll='ls -l'"},
+		{"it", "it is aliased to `echo it's'\n", 0, `alias it='echo it'\''s'`},
+		{"cd", "cd is a shell builtin\n", 0, "cd"},
+		{"f", "f is a function\nf () \n{ \n    :\n}\n", 0, "f"},
+		{"if", "if is a shell keyword\n", 0, "if"},
+		{"nope", "", 1, ""},
+	}
+	for _, c := range cases {
+		var runner, _ = newTestRunner(t, capturing(c.stdout, "", c.code, nil))
+		if got := runner.Resolve(c.cmd).CommandV(); got != c.want {
+			t.Errorf("%s: CommandV() = %q, want %q", c.cmd, got, c.want)
+		}
+	}
+}
+
+func TestCommandVMatchesBash(t *testing.T) {
+	var bash, err = exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+	for _, cmd := range []string{"ls", "cd", "if", "gotype-missing"} {
+		var want, _ = exec.Command(bash, "-c", "command -v "+shellQuote(cmd)).Output()
+		var runner, _ = newTestRunner(t, nil)
+		var got = runner.WithShell(bash).Resolve(cmd).CommandV()
+		if len(want) > 0 {
+			got += "\n"
+		}
+		if got != string(want) {
+			t.Errorf("%s: CommandV() = %q, command -v = %q", cmd, got, want)
+		}
+	}
+}
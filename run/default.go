@@ -0,0 +1,42 @@
+package run
+
+import (
+	"os"
+	"sync"
+)
+
+// 默认执行器及包级快捷函数
+//
+// 并发约定: SetDefault 与 TypeOf/PathOf/Default 可在多个协程中同时调用;
+// 执行器的 With* 配置方法不是并发安全的, 应在 SetDefault 之前配置完成, 之后不再修改.
+var (
+	defaultLocker sync.RWMutex
+	defaultRunner = NewRunner(os.Stderr, os.Stdin, os.Stdout)
+)
+
+// Default 当前默认执行器
+func Default() *Runner {
+	defaultLocker.RLock()
+	defer defaultLocker.RUnlock()
+	return defaultRunner
+}
+
+// SetDefault 替换默认执行器, nil 恢复为标准输入输出上的执行器
+func SetDefault(r *Runner) {
+	if r == nil {
+		r = NewRunner(os.Stderr, os.Stdin, os.Stdout)
+	}
+	defaultLocker.Lock()
+	defer defaultLocker.Unlock()
+	defaultRunner = r
+}
+
+// TypeOf 用默认执行器解析命令类型
+func TypeOf(cmd string) string {
+	return Default().Resolve(cmd).Type()
+}
+
+// PathOf 用默认执行器解析外部命令的路径
+func PathOf(cmd string) string {
+	return Default().Resolve(cmd).Path()
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestDefaultRace(t *testing.T) {
+	defer SetDefault(nil)
+	var runners = []*Runner{
+		NewRunner(nil, nil, nil).WithCommander(capturing("ls is /bin/ls\n", "", 0, nil)),
+		NewRunner(nil, nil, nil).WithCommander(capturing("ls is /usr/bin/ls\n", "", 0, nil)),
+		NewRunner(nil, nil, nil).WithCommander(capturing("ls is a shell builtin\n", "", 0, nil)),
+	}
+	SetDefault(runners[0])
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				SetDefault(runners[(i+n)%len(runners)])
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				switch TypeOf("ls") {
+				case TypeFile.String(), TypeBuiltin.String():
+				default:
+					t.Error("TypeOf returned an unexpected type")
+					return
+				}
+				switch PathOf("ls") {
+				case "/bin/ls", "/usr/bin/ls", "":
+				default:
+					t.Error("PathOf returned an unexpected path")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetDefaultNil(t *testing.T) {
+	var runner = NewRunner(nil, nil, nil)
+	SetDefault(runner)
+	if Default() != runner {
+		t.Error("Default() should return the runner set")
+	}
+	SetDefault(nil)
+	if Default() == nil || Default() == runner {
+		t.Error("SetDefault(nil) should restore a fresh standard runner")
+	}
+}
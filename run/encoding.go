@@ -0,0 +1,36 @@
+package run
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type EncodingPolicy int
+
+const (
+	EncodingSanitize EncodingPolicy = iota // 非法 UTF-8 替换为 U+FFFD 并告警(默认)
+	EncodingStrict                         // 非法 UTF-8 时返回 ErrInvalidEncoding
+)
+
+var (
+	ErrInvalidEncoding = errors.New(`type output is not valid UTF-8`)
+)
+
+// WithEncodingPolicy 设置底层 type 输出不是合法 UTF-8 时的处理方式, 透传模式始终输出原始字节
+func (r *Runner) WithEncodingPolicy(policy EncodingPolicy) *Runner {
+	r.encodingPolicy = policy
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) decode(cmd string, data []byte) (string, error) {
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	if r.encodingPolicy == EncodingStrict {
+		return ``, ErrInvalidEncoding
+	}
+	r.errLog("WARN:", cmd+`:`, ErrInvalidEncoding.Error()+`, invalid bytes replaced`)
+	return strings.ToValidUTF8(string(data), string(utf8.RuneError)), nil
+}
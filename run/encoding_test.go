@@ -0,0 +1,61 @@
+package run
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const invalidOutput = "ls is /opt/\xff\xfe/ls\n"
+
+func TestEncodingSanitize(t *testing.T) {
+	for _, flag := range []string{"type", "all", "path"} {
+		var runner, read = newTestRunner(t, capturing(invalidOutput, "", 0, nil))
+		var rs = runner.Exec(flag, "ls")
+		var stdout, stderr = read()
+		if rs.HasErr() {
+			t.Errorf("%s: err = %v, want none", flag, rs.Err())
+		}
+		if !utf8.Valid(stdout) {
+			t.Errorf("%s: stdout = %q, want valid UTF-8", flag, stdout)
+		}
+		if flag != "type" && !strings.Contains(string(stdout), string(utf8.RuneError)) {
+			t.Errorf("%s: stdout = %q, want U+FFFD", flag, stdout)
+		}
+		if !strings.Contains(string(stderr), "WARN: ls: "+ErrInvalidEncoding.Error()) {
+			t.Errorf("%s: stderr = %q, want encoding warning", flag, stderr)
+		}
+	}
+}
+
+func TestEncodingStrict(t *testing.T) {
+	for _, flag := range []string{"type", "all", "path"} {
+		var runner, read = newTestRunner(t, capturing(invalidOutput, "", 0, nil))
+		var rs = runner.WithEncodingPolicy(EncodingStrict).Exec(flag, "ls")
+		var stdout, stderr = read()
+		if !errors.Is(rs.Err(), ErrInvalidEncoding) {
+			t.Errorf("%s: err = %v, want ErrInvalidEncoding", flag, rs.Err())
+		}
+		if len(stdout) != 0 {
+			t.Errorf("%s: stdout = %q, want nothing", flag, stdout)
+		}
+		if !strings.Contains(string(stderr), ErrInvalidEncoding.Error()) {
+			t.Errorf("%s: stderr = %q, want the error logged", flag, stderr)
+		}
+	}
+}
+
+func TestNativeIgnoresEncodingPolicy(t *testing.T) {
+	for _, policy := range []EncodingPolicy{EncodingSanitize, EncodingStrict} {
+		var runner, read = newTestRunner(t, capturing(invalidOutput, "", 0, nil))
+		var rs = runner.WithEncodingPolicy(policy).Native("", "ls")
+		var stdout, stderr = read()
+		if string(stdout) != invalidOutput || len(stderr) != 0 {
+			t.Errorf("policy %d: stdout = %q, stderr = %q, want raw bytes only", policy, stdout, stderr)
+		}
+		if rs.HasErr() || rs.Type() != TypeFile.String() {
+			t.Errorf("policy %d: err = %v, type = %s", policy, rs.Err(), rs.Type())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package run
+
+import (
+	"strings"
+)
+
+type EnvFormat string
+
+const (
+	EnvFileSystemd EnvFormat = `env-file`        // systemd EnvironmentFile, 按双引号规则转义
+	EnvFileDocker  EnvFormat = `docker-env-file` // docker --env-file, 值按字面读取, 不支持引号与转义
+
+	envKeySuffix = `_PATH`
+	// 无需加引号的字符, 其余情况按双引号规则转义
+	envSafeChars = `ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789/._+-:@%,`
+)
+
+var (
+	envEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+)
+
+func init() {
+	RegisterCapability(Capability{Name: string(EnvFileSystemd), Kind: CapabilityFormat, Description: "COMMAND_PATH=/resolved/path lines for systemd EnvironmentFile"})
+	RegisterCapability(Capability{Name: string(EnvFileDocker), Kind: CapabilityFormat, Description: "COMMAND_PATH=/resolved/path lines for docker --env-file"})
+}
+
+// ParseEnvFormat 解析 env 文件格式名
+func ParseEnvFormat(v string) (EnvFormat, bool) {
+	switch EnvFormat(v) {
+	case EnvFileSystemd, EnvFileDocker:
+		return EnvFormat(v), true
+	}
+	return ``, false
+}
+
+// EnvKey 命令名转换为合法的环境变量名: 大写, 非字母数字替换为下划线, 以 _PATH 结尾
+// 不同命令可能得到同一个键(如 foo-bar 与 foo_bar), 见 EnvFile
+func EnvKey(cmd string) string {
+	var key strings.Builder
+	for i, c := range strings.ToUpper(cmd) {
+		switch {
+		case c >= 'A' && c <= 'Z', c == '_':
+			key.WriteRune(c)
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				key.WriteByte('_')
+			}
+			key.WriteRune(c)
+		default:
+			key.WriteByte('_')
+		}
+	}
+	return key.String() + envKeySuffix
+}
+
+// EnvLine systemd EnvironmentFile 格式的一行, 非外部命令及含换行符的路径输出为注释
+// systemd 在双引号内不解释 \n, 换行符无法以转义表示, 因此不输出
+func (rs *Result) EnvLine() string {
+	return rs.envLine(EnvFileSystemd)
+}
+
+// DockerEnvLine docker --env-file 格式的一行, 值原样输出
+// 非外部命令及含换行符等无法原样表示的路径输出为注释
+func (rs *Result) DockerEnvLine() string {
+	return rs.envLine(EnvFileDocker)
+}
+
+func (rs *Result) envLine(format EnvFormat) string {
+	var path = rs.Path()
+	if path == "" {
+		return envComment(rs.name, rs.Type()+`, skipped`)
+	}
+	if strings.ContainsAny(path, "\r\n") {
+		return envComment(rs.name, `path contains a line break, skipped`)
+	}
+	if format == EnvFileDocker {
+		return EnvKey(rs.name) + `=` + path
+	}
+	return EnvKey(rs.name) + `=` + envQuote(path)
+}
+
+// EnvFile 逐个解析命令并输出 env 文件, 键名冲突时保留先出现的命令, 其余输出为注释并告警
+// 返回的结果可用于退出码, 与 type 一致未找到的命令退出码非零
+func (r *Runner) EnvFile(names []string, format EnvFormat) []*Result {
+	var (
+		results = make([]*Result, 0, len(names))
+		owners  = map[string]string{}
+	)
+	for _, name := range names {
+		var rs = r.Resolve(name)
+		results = append(results, rs)
+		var line = rs.envLine(format)
+		if rs.Path() != "" {
+			var key = EnvKey(name)
+			if owner, ok := owners[key]; ok && owner != name {
+				r.errLog("WARN:", name+`:`, key, `already set by`, owner+`, skipped`)
+				line = envComment(name, key+` already set by `+owner+`, skipped`)
+			} else {
+				owners[key] = name
+			}
+		}
+		r.println(line)
+	}
+	return results
+}
+
+func envComment(cmd string, reason string) string {
+	return `# ` + strings.ReplaceAll(cmd, "\n", ` `) + `: ` + reason
+}
+
+func envQuote(v string) string {
+	if v != "" && strings.Trim(v, envSafeChars) == "" {
+		return v
+	}
+	return `"` + envEscaper.Replace(v) + `"`
+}
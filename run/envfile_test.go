@@ -0,0 +1,87 @@
+package run
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvKey(t *testing.T) {
+	var cases = map[string]string{
+		"ls":         "LS_PATH",
+		"git-lfs":    "GIT_LFS_PATH",
+		"7z":         "_7Z_PATH",
+		"python3.11": "PYTHON3_11_PATH",
+	}
+	for cmd, want := range cases {
+		if got := EnvKey(cmd); got != want {
+			t.Errorf("EnvKey(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+var envOutputs = map[string]string{
+	"ls":      "ls is /bin/ls\n",
+	"cd":      "cd is a shell builtin\n",
+	"foo-bar": "foo-bar is /opt/my tools/foo-bar\n",
+	"foo_bar": "foo_bar is /opt/bin/foo_bar\n",
+	"odd":     "odd is /opt/$HOME/\"odd\"\n",
+}
+
+func TestEnvFileSystemd(t *testing.T) {
+	var runner, read = newTestRunner(t, canned(envOutputs, nil))
+	var results = runner.EnvFile([]string{"ls", "cd", "nope", "foo-bar", "foo_bar", "odd"}, EnvFileSystemd)
+	var stdout, stderr = read()
+	var want = "LS_PATH=/bin/ls\n" +
+		"# cd: builtin, skipped\n" +
+		"# nope: unfound, skipped\n" +
+		"FOO_BAR_PATH=\"/opt/my tools/foo-bar\"\n" +
+		"# foo_bar: FOO_BAR_PATH already set by foo-bar, skipped\n" +
+		"ODD_PATH=\"/opt/\\$HOME/\\\"odd\\\"\"\n"
+	if string(stdout) != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if !strings.Contains(string(stderr), "WARN: foo_bar: FOO_BAR_PATH already set by foo-bar") {
+		t.Errorf("stderr = %q, want collision warning", stderr)
+	}
+	if len(results) != 6 || results[2].ExitCode() != 1 || results[1].ExitCode() != 0 {
+		t.Errorf("results = %d, want 6 with nope exiting 1 for the exit code", len(results))
+	}
+}
+
+func TestEnvFileDocker(t *testing.T) {
+	var outputs = map[string]string{
+		"ls":      envOutputs["ls"],
+		"cd":      envOutputs["cd"],
+		"foo-bar": envOutputs["foo-bar"],
+		"odd":     envOutputs["odd"],
+		"pad":     "pad is /opt/pad \n",
+	}
+	var runner, read = newTestRunner(t, canned(outputs, nil))
+	runner.EnvFile([]string{"ls", "cd", "foo-bar", "odd", "pad", "ls"}, EnvFileDocker)
+	var stdout, stderr = read()
+	var want = "LS_PATH=/bin/ls\n" +
+		"# cd: builtin, skipped\n" +
+		"FOO_BAR_PATH=/opt/my tools/foo-bar\n" +
+		"ODD_PATH=/opt/$HOME/\"odd\"\n" +
+		"PAD_PATH=/opt/pad\n" +
+		"LS_PATH=/bin/ls\n"
+	if string(stdout) != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if len(stderr) != 0 {
+		t.Errorf("stderr = %q, want no warning for a repeated command", stderr)
+	}
+}
+
+func TestEnvLineLineBreak(t *testing.T) {
+	for _, path := range []string{"/opt/a\nb", "/opt/a\rb"} {
+		var rs = &Result{name: "nl", kind: TypeFile, path: path}
+		var want = "# nl: path contains a line break, skipped"
+		if got := rs.EnvLine(); got != want {
+			t.Errorf("EnvLine() of %q = %q, want %q", path, got, want)
+		}
+		if got := rs.DockerEnvLine(); got != want {
+			t.Errorf("DockerEnvLine() of %q = %q, want %q", path, got, want)
+		}
+	}
+}
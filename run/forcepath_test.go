@@ -0,0 +1,63 @@
+package run
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForcePath(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		path  = filepath.Join(dir, "gotype-fp")
+		calls int32
+	)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// -P 不经过 type, 即使 type 报告为别名也只在 PATH 中查找
+	var runner, read = newTestRunner(t, capturing("gotype-fp is aliased to `ls'\n", "", 0, &calls))
+	runner.WithPathEnv(dir)
+	var found = runner.Exec("-P", "gotype-fp")
+	var missing = runner.Exec("force-path", "gotype-missing")
+	var stdout, _ = read()
+	if string(stdout) != path+"\n" {
+		t.Errorf("stdout = %q, want %q", stdout, path+"\n")
+	}
+	if found.Type() != TypeFile.String() || found.ExitCode() != 0 || calls != 0 {
+		t.Errorf("type = %s, code = %d, type calls = %d", found.Type(), found.ExitCode(), calls)
+	}
+	if missing.Type() != TypeUnFound.String() || missing.ExitCode() != 1 {
+		t.Errorf("missing: type = %s, code = %d, want unfound and 1", missing.Type(), missing.ExitCode())
+	}
+}
+
+func TestWithoutFunctions(t *testing.T) {
+	var args [][]string
+	var runner, _ = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+		args = append(args, command.Args[1:])
+		return &Capture{Stdout: []byte("ls is /bin/ls\n")}
+	}))
+	runner.WithoutFunctions(true)
+	runner.Resolve("ls")
+	runner.Native("type", "ls")
+	var want = []string{"-a -f ls", "-t -f ls"}
+	if len(args) != len(want) {
+		t.Fatalf("args = %q, want %q", args, want)
+	}
+	for i := range want {
+		if got := strings.Join(args[i], " "); got != want[i] {
+			t.Errorf("args[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestPathSkipsEmptyLine(t *testing.T) {
+	var runner, read = newTestRunner(t, capturing("cd is a shell builtin\n", "", 0, nil))
+	var rs = runner.Exec("path", "cd")
+	if stdout, _ := read(); len(stdout) != 0 || rs.ExitCode() != 0 {
+		t.Errorf("stdout = %q, code = %d; want no output like type -p", stdout, rs.ExitCode())
+	}
+}
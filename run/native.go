@@ -0,0 +1,189 @@
+package run
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "native", Kind: CapabilityFeature, Description: "byte-for-byte passthrough of the underlying type output"})
+}
+
+var (
+	longFlagMap = map[string]string{
+		"type":       "-t",
+		"all":        "-a",
+		"path":       "-p",
+		"force-path": "-P",
+	}
+)
+
+// Native 原样透传底层 type 的输出(字节级一致), 同时解析出结构化结果用于退出码
+// flag 为空时等价于不带选项的 type
+func (r *Runner) Native(flag string, cmd string) *Result {
+	var rs = NewResult()
+	rs.name = cmd
+	if err := r.check(); err != nil {
+		rs.err = err
+		rs.code = codeCannotExec
+		r.errLog("cmd err:", rs.err)
+		return rs
+	}
+	var args []string
+	if flag != "" {
+		if !strings.HasPrefix(flag, "-") {
+			flag = r.long2Short(flag)
+		}
+		args = append(args, flag)
+	}
+	if r.noFunctions {
+		args = append(args, `-f`)
+	}
+	var capture = r.capture(append(args, cmd))
+	r.write(capture.Stdout)
+	r.errWrite(capture.Stderr)
+	rs.code = capture.Code
+	rs.err = capture.Err
+	if rs.err != nil {
+		r.errLog("cmd err:", rs.err)
+	}
+	// -t/-p 输出不是描述格式, 仅 type 与 type -a 可解析出条目
+	// 透传时不受编码策略影响, 静默替换非法字节, 不额外告警或改变退出码
+	if flag == "" || r.short2Long(flag) == "all" {
+		r.parseText(rs, strings.ToValidUTF8(string(capture.Stdout), string(utf8.RuneError)))
+	}
+	if rs.kind == "" && capture.Failed() {
+		rs.kind = TypeUnFound
+	}
+	return rs
+}
+
+// WithoutFunctions 同 type -f, 解析时忽略 shell 函数
+func (r *Runner) WithoutFunctions(skip bool) *Runner {
+	r.noFunctions = skip
+	r.cache.clear()
+	return r
+}
+
+// Resolve 执行 type -a 并解析为结构化结果, 按缓存策略缓存
+// 缓存以命令名为键, 修改 shell/rc 文件/PATH/解析器等影响结果的配置时会清空
+func (r *Runner) Resolve(cmd string) *Result {
+	if rs, ok := r.cache.get(cmd); ok {
+		return rs
+	}
+	var rs = r.resolve(cmd)
+	r.warnCandidates(rs)
+	r.checkHash(rs)
+	if ok, ttl := r.cachePolicy()(rs); ok && ttl > 0 {
+		r.cache.set(cmd, rs, ttl)
+	}
+	return rs
+}
+
+func (r *Runner) resolve(cmd string) *Result {
+	if rs := r.resolveChain(cmd); rs != nil {
+		rs.creds = r.evalCreds
+		return rs
+	}
+	var (
+		rs   = NewResult()
+		args = []string{`-a`}
+	)
+	if r.noFunctions {
+		args = append(args, `-f`)
+	}
+	var capture = r.capture(append(args, cmd))
+	rs.name = cmd
+	rs.code = capture.Code
+	rs.err = capture.Err
+	rs.creds = r.evalCreds
+	r.parseInto(rs, capture.Stdout)
+	if capture.Failed() {
+		rs.kind = TypeUnFound
+	}
+	return rs
+}
+
+func (r *Runner) parseInto(rs *Result, stdout []byte) {
+	var text, err = r.decode(rs.name, stdout)
+	if err != nil {
+		rs.err = err
+		return
+	}
+	r.parseText(rs, text)
+}
+
+func (r *Runner) parseText(rs *Result, text string) {
+	rs.raw = text
+	rs.entries = parseEntries(rs.name, rs.raw)
+	if len(rs.entries) > 0 {
+		rs.kind = r.profile().classify(strings.TrimPrefix(rs.entries[0], rs.name))
+	}
+}
+
+// parseEntries 按 "<cmd> is ..." 切分 type -a 输出, 函数体等多行内容归入上一条
+func parseEntries(cmd string, output string) []string {
+	var (
+		prefix  = cmd + ` is `
+		entries []string
+	)
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, prefix) || len(entries) == 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			entries = append(entries, line)
+			continue
+		}
+		entries[len(entries)-1] += "\n" + line
+	}
+	for i, v := range entries {
+		entries[i] = strings.TrimRight(v, "\n")
+	}
+	return entries
+}
+
+// pathOf 提取 "<cmd> is /path" 或 "<cmd> is hashed (/path)" 中的路径
+func pathOf(cmd string, entry string) string {
+	var v = strings.TrimPrefix(strings.SplitN(entry, "\n", 2)[0], cmd+` is `)
+	if strings.HasPrefix(v, `hashed (`) && strings.HasSuffix(v, `)`) {
+		v = strings.TrimSuffix(strings.TrimPrefix(v, `hashed (`), `)`)
+	}
+	return strings.TrimSpace(v)
+}
+
+func (r *Runner) long2Short(flag string) string {
+	if v, ok := longFlagMap[strings.ToLower(flag)]; ok {
+		return v
+	}
+	return flag
+}
+
+func (r *Runner) write(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	var out = r.output
+	if out == nil {
+		out = os.Stdout
+	}
+	if n, err := out.Write(data); err == nil {
+		return n
+	}
+	return 0
+}
+
+func (r *Runner) errWrite(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	var out = r.err
+	if out == nil {
+		out = os.Stderr
+	}
+	if n, err := out.Write(data); err == nil {
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package run
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// commanderFunc 以函数模拟子进程执行
+type commanderFunc func(command *exec.Cmd) *Capture
+
+func (fn commanderFunc) Run(command *exec.Cmd) *Capture {
+	return fn(command)
+}
+
+// capturing 返回固定输出的模拟执行器, 并统计调用次数
+func capturing(stdout, stderr string, code int, calls *int32) Commander {
+	return commanderFunc(func(*exec.Cmd) *Capture {
+		if calls != nil {
+			atomic.AddInt32(calls, 1)
+		}
+		return &Capture{Stdout: []byte(stdout), Stderr: []byte(stderr), Code: code}
+	})
+}
+
+// canned 按命令名(最后一个参数)返回预设的 type 输出, 未预设的命令以退出码 1 表示不存在
+// calls 不为 nil 时统计每个命令的调用次数
+func canned(outputs map[string]string, calls map[string]int) Commander {
+	return commanderFunc(func(command *exec.Cmd) *Capture {
+		var name = command.Args[len(command.Args)-1]
+		if calls != nil {
+			calls[name]++
+		}
+		if v, ok := outputs[name]; ok {
+			return &Capture{Stdout: []byte(v)}
+		}
+		return &Capture{Code: 1}
+	})
+}
+
+// newTestRunner 输出写入临时文件的执行器, 返回读取 stdout/stderr 的函数
+func newTestRunner(t *testing.T, commander Commander) (*Runner, func() ([]byte, []byte)) {
+	t.Helper()
+	var (
+		dir        = t.TempDir()
+		out, err1  = os.Create(filepath.Join(dir, "stdout"))
+		errs, err2 = os.Create(filepath.Join(dir, "stderr"))
+	)
+	if err1 != nil || err2 != nil {
+		t.Fatal(err1, err2)
+	}
+	t.Cleanup(func() {
+		_ = out.Close()
+		_ = errs.Close()
+	})
+	var runner = NewRunner(errs, nil, out).WithCommander(commander)
+	return runner, func() ([]byte, []byte) {
+		var stdout, _ = os.ReadFile(out.Name())
+		var stderr, _ = os.ReadFile(errs.Name())
+		return stdout, stderr
+	}
+}
+
+func TestNativeIsByteIdentical(t *testing.T) {
+	var cases = []struct {
+		name   string
+		stdout string
+		stderr string
+		code   int
+		kind   string
+	}{
+		{"no trailing newline", "ls is /bin/ls", "", 0, "file"},
+		{"extra newlines", "ls is /usr/bin/ls\nls is /bin/ls\n\n\n", "", 0, "file"},
+		{"stderr and exit code", "", "bash: type: nope: not found\n", 1, "unfound"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var runner, read = newTestRunner(t, capturing(c.stdout, c.stderr, c.code, nil))
+			var rs = runner.Native("", "ls")
+			var stdout, stderr = read()
+			if !bytes.Equal(stdout, []byte(c.stdout)) {
+				t.Errorf("stdout = %q, want %q", stdout, c.stdout)
+			}
+			if !bytes.Equal(stderr, []byte(c.stderr)) {
+				t.Errorf("stderr = %q, want %q", stderr, c.stderr)
+			}
+			if rs.ExitCode() != c.code {
+				t.Errorf("exit code = %d, want %d", rs.ExitCode(), c.code)
+			}
+			if rs.Type() != c.kind {
+				t.Errorf("type = %s, want %s", rs.Type(), c.kind)
+			}
+		})
+	}
+}
+
+func TestNativePassesFlag(t *testing.T) {
+	var args []string
+	var runner, read = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+		args = command.Args[1:]
+		return &Capture{Stdout: []byte("file\n")}
+	}))
+	runner.Native("type", "ls")
+	if len(args) != 2 || args[0] != "-t" || args[1] != "ls" {
+		t.Errorf("args = %q, want [-t ls]", args)
+	}
+	if stdout, _ := read(); string(stdout) != "file\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+}
@@ -0,0 +1,18 @@
+package run
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoPackageOwner = errors.New(`no package owns the resolved file`)
+)
+
+// PackageOwner 解析出的文件所属的系统软件包, 仅 linux 支持(dpkg/rpm)
+func (r *Runner) PackageOwner(rs *Result) (string, error) {
+	var path = rs.Path()
+	if path == "" {
+		return ``, ErrNotFile
+	}
+	return r.packageOwner(path)
+}
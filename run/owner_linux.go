@@ -0,0 +1,43 @@
+package run
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "package-owner", Kind: CapabilityFeature, Description: "owning package of a resolved file via dpkg or rpm", Platform: "linux"})
+}
+
+// packageOwner 依次尝试 dpkg-query -S 与 rpm -qf; merged-usr 系统中 dpkg 可能只登记了 /bin 下的路径
+func (r *Runner) packageOwner(path string) (string, error) {
+	var (
+		commander = r.commander
+		paths     = []string{path}
+	)
+	if commander == nil {
+		commander = execCommander{}
+	}
+	if strings.HasPrefix(path, `/usr/`) {
+		paths = append(paths, strings.TrimPrefix(path, `/usr`))
+	}
+	if v, err := filepath.EvalSymlinks(path); err == nil && v != path {
+		paths = append(paths, v)
+	}
+	for _, v := range paths {
+		// dpkg 输出 "coreutils: /bin/ls"
+		if capture := commander.Run(exec.Command(`dpkg-query`, `-S`, v)); !capture.Failed() {
+			var line = strings.SplitN(string(capture.Stdout), "\n", 2)[0]
+			if i := strings.Index(line, `: `); i > 0 {
+				return line[:i], nil
+			}
+		}
+		if capture := commander.Run(exec.Command(`rpm`, `-qf`, v)); !capture.Failed() {
+			if owner := strings.TrimSpace(strings.SplitN(string(capture.Stdout), "\n", 2)[0]); owner != "" {
+				return owner, nil
+			}
+		}
+	}
+	return ``, ErrNoPackageOwner
+}
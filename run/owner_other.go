@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package run
+
+import (
+	"errors"
+	"runtime"
+)
+
+func (r *Runner) packageOwner(string) (string, error) {
+	return ``, errors.New(`package owner lookup is not supported on ` + runtime.GOOS)
+}
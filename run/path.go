@@ -0,0 +1,95 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "hash-check", Kind: CapabilityFeature, Description: "detects stale hashed paths against a fresh PATH scan"})
+}
+
+// LookPath 纯 Go 按 pathEnv 查找当前用户可执行的文件, 不经过 shell 的 hash 表
+// 空目录项与 . 表示当前目录, 与 bash 一致返回 ./cmd 形式的相对路径
+func LookPath(cmd string, pathEnv string) string {
+	return lookPath(cmd, pathEnv, currentCredentials())
+}
+
+func lookPath(cmd string, pathEnv string, creds credentials) string {
+	if strings.Contains(cmd, `/`) {
+		if isExecutableFile(cmd, creds) {
+			return cmd
+		}
+		return ``
+	}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = `.`
+		}
+		var path = filepath.Join(dir, cmd)
+		if isExecutableFile(path, creds) {
+			if !strings.ContainsRune(path, filepath.Separator) {
+				path = `.` + string(filepath.Separator) + path
+			}
+			return path
+		}
+	}
+	return ``
+}
+
+// isExecutableFile 与 bash 查找命令时一致, 要求是普通文件且对给定凭据可执行, 仅有他人的执行位不算
+func isExecutableFile(path string, creds credentials) bool {
+	var info, err = os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return IsExecutableBy(path, creds.uid, creds.gid)
+}
+
+// WithHashCheck 解析时额外以 type -P 取 shell 的 hash 表中的路径, 并以纯 Go 重新扫描 PATH 比对
+// type -a 不查 hash 表, 因此不能用其结果判断; 子进程也看不到调用方 shell 的 hash 表,
+// 只能发现被检查的 shell 自身(如 rc 文件中的 hash -p)记录的过期路径
+func (r *Runner) WithHashCheck(check bool) *Runner {
+	r.hashCheck = check
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) pathEnv() string {
+	if r.pathSet {
+		return r.pathOverride
+	}
+	return os.Getenv(`PATH`)
+}
+
+func (r *Runner) checkHash(rs *Result) {
+	if !r.hashCheck || rs.kind != TypeFile {
+		return
+	}
+	var capture = r.capture([]string{`-P`, rs.name})
+	if capture.Failed() {
+		return
+	}
+	rs.hashChecked = true
+	rs.hashedPath = strings.TrimSpace(strings.SplitN(string(capture.Stdout), "\n", 2)[0])
+	rs.freshPath = LookPath(rs.name, r.pathEnv())
+	if rs.HashStale() {
+		r.errLog("WARN:", rs.name+`: shell hashed`, rs.HashedPath(), `but PATH now resolves to`, rs.FreshPath())
+	}
+}
+
+// HashedPath 启用 hash 检查时, type -P 报告的路径(命中 hash 表时为其中记录的路径)
+func (rs *Result) HashedPath() string {
+	return rs.hashedPath
+}
+
+// FreshPath 启用 hash 检查时, 纯 Go 扫描 PATH 得到的路径
+func (rs *Result) FreshPath() string {
+	return rs.freshPath
+}
+
+// HashStale shell 使用的路径与当前 PATH 扫描结果不一致, 即 hash 表中的路径已过期
+func (rs *Result) HashStale() bool {
+	return rs.hashChecked && rs.hashedPath != rs.freshPath
+}
@@ -0,0 +1,125 @@
+package run
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// executable 在 dir 下创建可执行文件并返回其路径
+func executable(t *testing.T, dir string, name string) string {
+	t.Helper()
+	var path = filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHashCheck(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		fresh = executable(t, dir, "ls")
+	)
+	var cases = []struct {
+		name   string
+		hashed string
+		stale  bool
+	}{
+		{"stale", "/old/bin/ls", true},
+		{"current", fresh, false},
+	}
+	for _, c := range cases {
+		var args [][]string
+		var runner, read = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+			args = append(args, command.Args[1:])
+			if command.Args[1] == "-P" {
+				return &Capture{Stdout: []byte(c.hashed + "\n")}
+			}
+			// type -a 不查 hash 表, 报告的总是 PATH 扫描结果
+			return &Capture{Stdout: []byte("ls is " + fresh + "\n")}
+		}))
+		var rs = runner.WithPathEnv(dir).WithHashCheck(true).Resolve("ls")
+		var _, stderr = read()
+		if len(args) != 2 || strings.Join(args[1], " ") != "-P ls" {
+			t.Errorf("%s: args = %q, want type -a then type -P", c.name, args)
+		}
+		if rs.HashStale() != c.stale || rs.HashedPath() != c.hashed || rs.FreshPath() != fresh {
+			t.Errorf("%s: stale = %v, hashed = %q, fresh = %q", c.name, rs.HashStale(), rs.HashedPath(), rs.FreshPath())
+		}
+		if strings.Contains(string(stderr), "WARN: ls: shell hashed /old/bin/ls") != c.stale {
+			t.Errorf("%s: stderr = %q", c.name, stderr)
+		}
+	}
+}
+
+func TestHashCheckSkipsNonFile(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, capturing("cd is a shell builtin\n", "", 0, &calls))
+	var rs = runner.WithHashCheck(true).Resolve("cd")
+	if calls != 1 || rs.HashStale() {
+		t.Errorf("calls = %d, stale = %v, want builtins left unchecked", calls, rs.HashStale())
+	}
+}
+
+func TestHashCheckWithRcFile(t *testing.T) {
+	var bash, err = exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+	var (
+		dir    = t.TempDir()
+		fresh  = executable(t, dir, "gotype-hashed")
+		old    = executable(t, t.TempDir(), "gotype-hashed")
+		rcFile = filepath.Join(dir, "rc")
+	)
+	if err = os.WriteFile(rcFile, []byte("hash -p "+shellQuote(old)+" gotype-hashed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var runner, _ = newTestRunner(t, nil)
+	var rs = runner.WithShell(bash).WithRcFile(rcFile).WithPathEnv(dir).WithHashCheck(true).Resolve("gotype-hashed")
+	if !rs.HashStale() || rs.HashedPath() != old || rs.FreshPath() != fresh {
+		t.Errorf("stale = %v, hashed = %q, fresh = %q; want %q hashed over %q", rs.HashStale(), rs.HashedPath(), rs.FreshPath(), old, fresh)
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	var dir, err = filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		bin  = filepath.Join(dir, "bin")
+		sep  = string(filepath.ListSeparator)
+		self = "." + string(filepath.Separator) + "gotype-zz"
+	)
+	if err = os.Mkdir(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	executable(t, dir, "gotype-zz")
+	executable(t, bin, "gotype-zz")
+	if err = os.WriteFile(filepath.Join(bin, "plain"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, dir)
+	var cases = []struct {
+		cmd  string
+		path string
+		want string
+	}{
+		{"gotype-zz", sep, self},
+		{"gotype-zz", "", ""},
+		{"gotype-zz", ".", self},
+		{"gotype-zz", "bin" + sep + ".", filepath.Join("bin", "gotype-zz")},
+		{"gotype-zz", bin + sep, filepath.Join(bin, "gotype-zz")},
+		{"plain", bin, ""},
+		{"bin/gotype-zz", "", "bin/gotype-zz"},
+	}
+	for _, c := range cases {
+		if got := LookPath(c.cmd, c.path); got != c.want {
+			t.Errorf("LookPath(%q, %q) = %q, want %q", c.cmd, c.path, got, c.want)
+		}
+	}
+}
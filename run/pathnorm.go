@@ -0,0 +1,77 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// PathChange 规范化 PATH 时被改写或移除的目录项
+	PathChange struct {
+		Entry  string // 原始目录项
+		Result string // 规范化后的目录, 为空表示已移除
+		Reason string
+	}
+)
+
+// NormalizePath 规范化 PATH: 移除空项(即当前目录), 相对路径与 . / .. 转为绝对路径, 解析目录中的软链
+func NormalizePath(raw string) (string, []PathChange) {
+	var (
+		dirs    []string
+		changes []PathChange
+	)
+	for _, entry := range filepath.SplitList(raw) {
+		if entry == "" {
+			changes = append(changes, PathChange{Entry: entry, Reason: `empty entry means the current directory`})
+			continue
+		}
+		var (
+			dir     = entry
+			reasons []string
+		)
+		if !filepath.IsAbs(dir) {
+			if abs, err := filepath.Abs(dir); err == nil {
+				dir = abs
+				reasons = append(reasons, `relative to the current directory`)
+			}
+		}
+		if v := filepath.Clean(dir); v != dir {
+			dir = v
+			reasons = append(reasons, `cleaned`)
+		}
+		if v, err := filepath.EvalSymlinks(dir); err == nil && v != dir {
+			dir = v
+			reasons = append(reasons, `symlink resolved`)
+		}
+		if len(reasons) > 0 {
+			changes = append(changes, PathChange{Entry: entry, Result: dir, Reason: strings.Join(reasons, `, `)})
+		}
+		dirs = append(dirs, dir)
+	}
+	return strings.Join(dirs, string(filepath.ListSeparator)), changes
+}
+
+// WithPathEnv 解析时使用的 PATH, 未设置时沿用当前进程的 PATH
+// 空字符串表示 PATH 为空(如规范化后全部目录项都被移除), 不会回退到进程的 PATH
+func (r *Runner) WithPathEnv(path string) *Runner {
+	r.pathOverride = path
+	r.pathSet = true
+	r.cache.clear()
+	return r
+}
+
+// environ 子进程环境变量, 按需替换 PATH
+func (r *Runner) environ() []string {
+	var env = os.Environ()
+	if !r.pathSet {
+		return env
+	}
+	var items = make([]string, 0, len(env)+1)
+	for _, v := range env {
+		if !strings.HasPrefix(v, `PATH=`) {
+			items = append(items, v)
+		}
+	}
+	return append(items, `PATH=`+r.pathOverride)
+}
@@ -0,0 +1,92 @@
+package run
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdir 切换到 dir, 测试结束后恢复
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	var wd, err = os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
+
+func TestNormalizePath(t *testing.T) {
+	var dir, err = filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		real = filepath.Join(dir, "real")
+		link = filepath.Join(dir, "link")
+		sep  = string(filepath.ListSeparator)
+	)
+	if err = os.Mkdir(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(real, link); err != nil {
+		t.Skip("symlinks unsupported:", err)
+	}
+	chdir(t, dir)
+	var path, changes = NormalizePath(strings.Join([]string{"/usr/bin", "", ".", "real/../real", link}, sep))
+	if want := strings.Join([]string{"/usr/bin", dir, real, real}, sep); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	var want = []PathChange{
+		{Entry: "", Result: "", Reason: "empty entry means the current directory"},
+		{Entry: ".", Result: dir, Reason: "relative to the current directory"},
+		{Entry: "real/../real", Result: real, Reason: "relative to the current directory"},
+		{Entry: link, Result: real, Reason: "symlink resolved"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestNormalizeDropsEveryEntry(t *testing.T) {
+	var sep = string(filepath.ListSeparator)
+	var path, changes = NormalizePath(sep)
+	if path != "" || len(changes) != 2 {
+		t.Errorf("path = %q, changes = %+v, want an empty PATH", path, changes)
+	}
+}
+
+func TestWithEmptyPathEnv(t *testing.T) {
+	var calls int32
+	var runner, _ = newTestRunner(t, capturing("", "", 1, &calls))
+	var rs = runner.WithPathEnv("").Exec("force-path", "ls")
+	if rs.Get() != "" || rs.ExitCode() != 1 {
+		t.Errorf("output = %q, code = %d; want the process PATH ignored", rs.Get(), rs.ExitCode())
+	}
+	var env []string
+	runner.WithCommander(commanderFunc(func(command *exec.Cmd) *Capture {
+		env = command.Env
+		return &Capture{Code: 1}
+	})).Resolve("ls")
+	var paths []string
+	for _, v := range env {
+		if strings.HasPrefix(v, "PATH=") {
+			paths = append(paths, v)
+		}
+	}
+	if len(paths) != 1 || paths[0] != "PATH=" {
+		t.Errorf("PATH in subprocess env = %q, want [PATH=]", paths)
+	}
+}
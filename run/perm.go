@@ -0,0 +1,45 @@
+package run
+
+import (
+	"os"
+)
+
+type credentials struct {
+	uid int
+	gid int
+}
+
+// WithEvalCredentials 以指定 uid/gid 评估结果的可执行性, 用于审计其他账号能否执行命令
+// gid 为账号的主组, 附加组不参与判断
+func (r *Runner) WithEvalCredentials(uid, gid int) *Runner {
+	r.evalCreds = &credentials{uid: uid, gid: gid}
+	r.cache.clear()
+	return r
+}
+
+func currentCredentials() credentials {
+	return credentials{uid: os.Getuid(), gid: os.Getgid()}
+}
+
+// IsExecutable 解析出的文件对评估凭据(默认当前用户)是否可执行
+func (rs *Result) IsExecutable() bool {
+	var path = rs.Path()
+	if path == "" {
+		return false
+	}
+	var creds = currentCredentials()
+	if rs.creds != nil {
+		creds = *rs.creds
+	}
+	return IsExecutableBy(path, creds.uid, creds.gid)
+}
+
+// IsExecutableByCurrentUser 解析出的文件对当前用户是否可执行
+func (rs *Result) IsExecutableByCurrentUser() bool {
+	var path = rs.Path()
+	if path == "" {
+		return false
+	}
+	var creds = currentCredentials()
+	return IsExecutableBy(path, creds.uid, creds.gid)
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package run
+
+import (
+	"os"
+	"syscall"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "eval-credentials", Kind: CapabilityFeature, Description: "executability evaluated against a given uid/gid", Platform: "unix"})
+}
+
+// IsExecutableBy 按 owner/group/other 权限位判断 uid/gid 能否执行文件, root 只需任一执行位
+// 只比较给定的主组 gid, 不考虑附加组: 仅通过附加组获得执行权限的账号会被判为不可执行
+// 同样不考虑 ACL 与 noexec 挂载
+func IsExecutableBy(path string, uid, gid int) bool {
+	var info, err = os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	var (
+		perm   = info.Mode().Perm()
+		st, ok = info.Sys().(*syscall.Stat_t)
+	)
+	if !ok || uid == 0 {
+		return perm&0111 != 0
+	}
+	if uint32(uid) == st.Uid {
+		return perm&0100 != 0
+	}
+	if uint32(gid) == st.Gid {
+		return perm&0010 != 0
+	}
+	return perm&0001 != 0
+}
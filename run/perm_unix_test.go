@@ -0,0 +1,110 @@
+//go:build !windows
+// +build !windows
+
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsExecutableBy(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "tool")
+		uid  = os.Getuid()
+		gid  = os.Getgid()
+	)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// root 运行时把文件交给虚构账号, 否则 owner 用例会落入 root 规则
+	if uid == 0 {
+		uid, gid = 4242, 4343
+		if err := os.Chown(path, uid, gid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		otherUID = uid + 1000
+		otherGID = gid + 1000
+	)
+	var cases = []struct {
+		mode  os.FileMode
+		uid   int
+		gid   int
+		want  bool
+		label string
+	}{
+		{0700, uid, gid, true, "owner with owner bit"},
+		{0070, uid, gid, false, "owner ignores group bit"},
+		{0007, uid, gid, false, "owner ignores other bit"},
+		{0070, otherUID, gid, true, "group with group bit"},
+		{0700, otherUID, gid, false, "group without group bit"},
+		{0007, otherUID, gid, false, "group ignores other bit"},
+		{0001, otherUID, otherGID, true, "other with other bit"},
+		{0770, otherUID, otherGID, false, "other without other bit"},
+		{0100, 0, 0, true, "root with any execute bit"},
+		{0644, 0, 0, false, "root without execute bits"},
+	}
+	for _, c := range cases {
+		if err := os.Chmod(path, c.mode); err != nil {
+			t.Fatal(err)
+		}
+		if got := IsExecutableBy(path, c.uid, c.gid); got != c.want {
+			t.Errorf("%s: IsExecutableBy(%o, %d, %d) = %v, want %v", c.label, c.mode, c.uid, c.gid, got, c.want)
+		}
+	}
+	if IsExecutableBy(filepath.Dir(path), 0, 0) {
+		t.Error("directories are not executable commands")
+	}
+	if IsExecutableBy(path+".missing", 0, 0) {
+		t.Error("missing file reported executable")
+	}
+}
+
+func TestResultIsExecutableWithEvalCredentials(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		uid, gid  = os.Getuid(), os.Getgid()
+		runner, _ = newTestRunner(t, capturing("tool is "+path+"\n", "", 0, nil))
+	)
+	if uid == 0 {
+		uid, gid = 4242, 4343
+		if err := os.Chown(path, uid, gid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rs := runner.WithEvalCredentials(uid, gid).Resolve("tool"); !rs.IsExecutable() {
+		t.Error("owner should be able to execute a 0700 file")
+	}
+	if rs := runner.WithEvalCredentials(uid+1000, gid+1000).Resolve("tool"); rs.IsExecutable() {
+		t.Error("another account should not execute a 0700 file")
+	}
+}
+
+func TestLookPathSkipsFilesNotExecutableByUser(t *testing.T) {
+	var (
+		private = t.TempDir()
+		shared  = t.TempDir()
+		pathEnv = private + string(filepath.ListSeparator) + shared
+		want    = filepath.Join(shared, "tool")
+		creds   = credentials{uid: os.Getuid() + 1000, gid: os.Getgid() + 1000}
+	)
+	// private 中的 0700 文件属于当前账号, 其他账号不可执行, 应继续在 shared 中查找
+	if err := os.WriteFile(filepath.Join(private, "tool"), []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(want, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := lookPath("tool", pathEnv, creds); got != want {
+		t.Errorf("lookPath as another user = %q, want %q", got, want)
+	}
+	if got := lookPath("tool", pathEnv, currentCredentials()); got != filepath.Join(private, "tool") {
+		t.Errorf("lookPath as the owner = %q, want the private file", got)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package run
+
+import (
+	"os"
+)
+
+// IsExecutableBy windows 无 unix 权限位, 存在的普通文件即视为可执行
+func IsExecutableBy(path string, _, _ int) bool {
+	var info, err = os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,160 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// 外部解析插件协议(类似 git 子命令插件):
+//
+//   - 插件为 PATH 中名为 gotype-resolver-<name> 的可执行文件
+//   - 标准输入: 待解析的命令名, 以换行结尾
+//   - 标准输出: 退出码为 0 时输出一个 JSON 对象
+//     {"type": "file", "path": "/usr/bin/ls", "entries": ["ls is /usr/bin/ls", "ls is /bin/ls"]}
+//     {"type": "alias", "alias": "ls -l"}
+//     type 取值同 type -t (alias/keyword/function/builtin/file/unfound);
+//     file 必须给出 path, alias 必须给出定义 alias, 二者直接作为结果的路径与别名定义;
+//     entries 为 type -a 的输出行, 可省略, 省略时按 bash 的措辞生成
+//   - 退出码: 0 已解析; 1 无法解析, 交给解析链中的下一个; 其他视为插件错误
+
+type (
+	// Resolver 解析链中的一环, 返回 nil, nil 表示交给下一个
+	Resolver interface {
+		Resolve(cmd string) (*Result, error)
+	}
+
+	// PluginResolver 调用外部 gotype-resolver-<name> 插件解析
+	PluginResolver struct {
+		name      string
+		path      string
+		commander Commander
+	}
+
+	pluginResult struct {
+		Type    string   `json:"type"`
+		Path    string   `json:"path"`
+		Alias   string   `json:"alias"`
+		Entries []string `json:"entries"`
+	}
+)
+
+const (
+	pluginPrefix       = `gotype-resolver-`
+	codePluginDeclined = 1
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "plugin", Kind: CapabilityBackend, Description: "external " + pluginPrefix + "<name> resolvers on PATH"})
+}
+
+// NewPluginResolver 在 PATH 中查找 gotype-resolver-<name>
+func NewPluginResolver(name string) (*PluginResolver, error) {
+	var path, err = exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return &PluginResolver{name: name, path: path, commander: execCommander{}}, nil
+}
+
+// WithCommander 替换插件的子进程执行器
+func (p *PluginResolver) WithCommander(commander Commander) *PluginResolver {
+	if commander != nil {
+		p.commander = commander
+	}
+	return p
+}
+
+func (p *PluginResolver) Resolve(cmd string) (*Result, error) {
+	var command = exec.Command(p.path)
+	command.Stdin = strings.NewReader(cmd + "\n")
+	var capture = p.commander.Run(command)
+	if capture.Err != nil {
+		return nil, capture.Err
+	}
+	switch capture.Code {
+	case 0:
+	case codePluginDeclined:
+		return nil, nil
+	default:
+		return nil, errors.New(pluginPrefix + p.name + `: exit ` + strconv.Itoa(capture.Code) + `: ` + strings.TrimSpace(string(capture.Stderr)))
+	}
+	var data pluginResult
+	if err := json.Unmarshal(capture.Stdout, &data); err != nil {
+		return nil, errors.New(pluginPrefix + p.name + `: invalid result: ` + err.Error())
+	}
+	return data.result(cmd)
+}
+
+func (data pluginResult) result(cmd string) (*Result, error) {
+	var kind = commandType(data.Type)
+	if !kind.valid() {
+		return nil, errors.New(`plugin result type undefined: ` + data.Type)
+	}
+	switch {
+	case kind == TypeFile && data.Path == "":
+		return nil, errors.New(`plugin file result without path`)
+	case kind == TypeAlias && data.Alias == "":
+		return nil, errors.New(`plugin alias result without alias`)
+	}
+	var rs = NewResult()
+	rs.name = cmd
+	rs.kind = kind
+	rs.path = data.Path
+	rs.alias = data.Alias
+	rs.entries = data.Entries
+	if kind == TypeUnFound {
+		rs.code = 1
+		return rs, nil
+	}
+	if len(rs.entries) <= 0 {
+		rs.entries = []string{data.entry(cmd)}
+	}
+	rs.raw = strings.Join(rs.entries, "\n") + "\n"
+	return rs, nil
+}
+
+// entry 未给出 entries 时按 bash 的措辞生成 type -a 的输出行
+func (data pluginResult) entry(cmd string) string {
+	switch commandType(data.Type) {
+	case TypeFile:
+		return cmd + ` is ` + data.Path
+	case TypeAlias:
+		return cmd + " is aliased to `" + data.Alias + `'`
+	case TypeFunction:
+		return cmd + ` is a function`
+	}
+	return cmd + ` is a shell ` + data.Type
+}
+
+func (ty commandType) valid() bool {
+	for _, v := range types {
+		if v == ty {
+			return true
+		}
+	}
+	return false
+}
+
+// WithResolver 在 type 之前依次尝试的解析器
+func (r *Runner) WithResolver(resolvers ...Resolver) *Runner {
+	r.resolvers = append(r.resolvers, resolvers...)
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) resolveChain(cmd string) *Result {
+	for _, v := range r.resolvers {
+		var rs, err = v.Resolve(cmd)
+		if err != nil {
+			r.errLog("WARN:", err.Error())
+			continue
+		}
+		if rs != nil {
+			return rs
+		}
+	}
+	return nil
+}
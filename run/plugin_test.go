@@ -0,0 +1,128 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 按标准输入的命令名给出不同响应的插件
+const fakePlugin = `#!/bin/sh
+read cmd
+case "$cmd" in
+ls) echo '{"type": "file", "path": "/opt/ls"}' ;;
+vi) echo '{"type": "file", "path": "/opt/vim", "entries": ["vi is a link to vim", "vi is /usr/bin/vi"]}' ;;
+ll) echo '{"type": "alias", "alias": "ls -l"}' ;;
+cd) echo '{"type": "builtin"}' ;;
+gone) echo '{"type": "unfound"}' ;;
+nopath) echo '{"type": "file"}' ;;
+noalias) echo '{"type": "alias", "entries": ["noalias is aliased to x"]}' ;;
+skip) exit 1 ;;
+bad) echo 'not json' ;;
+odd) echo '{"type": "widget"}' ;;
+*) echo "boom: $cmd" >&2; exit 3 ;;
+esac
+`
+
+func fakeResolver(t *testing.T) *PluginResolver {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need /bin/sh")
+	}
+	var dir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, pluginPrefix+"fake"), []byte(fakePlugin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(filepath.ListSeparator)+os.Getenv("PATH"))
+	var resolver, err = NewPluginResolver("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver
+}
+
+func TestPluginResolver(t *testing.T) {
+	var resolver = fakeResolver(t)
+	var cases = []struct {
+		cmd      string
+		kind     string
+		entries  string
+		commandV string
+		code     int
+		err      string
+	}{
+		{"ls", "file", "ls is /opt/ls", "/opt/ls", 0, ""},
+		{"vi", "file", "vi is a link to vim\nvi is /usr/bin/vi", "/opt/vim", 0, ""},
+		{"ll", "alias", "ll is aliased to `ls -l'", "alias ll='ls -l'", 0, ""},
+		{"cd", "builtin", "cd is a shell builtin", "cd", 0, ""},
+		{"gone", "unfound", "", "", 1, ""},
+		{"skip", "", "", "", 0, ""},
+		{"nopath", "", "", "", 0, "plugin file result without path"},
+		{"noalias", "", "", "", 0, "plugin alias result without alias"},
+		{"bad", "", "", "", 0, pluginPrefix + "fake: invalid result:"},
+		{"odd", "", "", "", 0, "plugin result type undefined: widget"},
+		{"nope", "", "", "", 0, pluginPrefix + "fake: exit 3: boom: nope"},
+	}
+	for _, c := range cases {
+		var rs, err = resolver.Resolve(c.cmd)
+		if c.err != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+				t.Errorf("%s: err = %v, want %q", c.cmd, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: err = %v", c.cmd, err)
+			continue
+		}
+		if c.kind == "" {
+			if rs != nil {
+				t.Errorf("%s: result = %+v, want nil to decline", c.cmd, rs)
+			}
+			continue
+		}
+		if rs == nil || rs.Type() != c.kind || strings.Join(rs.Entries(), "\n") != c.entries || rs.ExitCode() != c.code {
+			t.Errorf("%s: result = %+v", c.cmd, rs)
+			continue
+		}
+		if got := rs.CommandV(); got != c.commandV {
+			t.Errorf("%s: CommandV() = %q, want %q", c.cmd, got, c.commandV)
+		}
+	}
+}
+
+func TestPluginChain(t *testing.T) {
+	var resolver = fakeResolver(t)
+	var cases = []struct {
+		cmd   string
+		path  string
+		calls int32
+		warn  bool
+	}{
+		{"ls", "/opt/ls", 0, false},
+		{"skip", "/bin/skip", 1, false},
+		{"bad", "/bin/bad", 1, true},
+		{"nope", "/bin/nope", 1, true},
+	}
+	for _, c := range cases {
+		var calls int32
+		var runner, read = newTestRunner(t, capturing(c.cmd+" is /bin/"+c.cmd+"\n", "", 0, &calls))
+		var rs = runner.WithResolver(resolver).Resolve(c.cmd)
+		var _, stderr = read()
+		if rs.Path() != c.path || calls != c.calls {
+			t.Errorf("%s: path = %q, type calls = %d; want %q, %d", c.cmd, rs.Path(), calls, c.path, c.calls)
+		}
+		if strings.Contains(string(stderr), "WARN: "+pluginPrefix+"fake") != c.warn {
+			t.Errorf("%s: stderr = %q, want warning %v", c.cmd, stderr, c.warn)
+		}
+	}
+}
+
+func TestNewPluginResolverMissing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := NewPluginResolver("missing"); err == nil {
+		t.Error("want an error for a plugin not on PATH")
+	}
+}
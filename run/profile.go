@@ -0,0 +1,81 @@
+package run
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// profile 不同 shell 的 type 调用方式与输出措辞
+	profile struct {
+		name     string
+		quote    func(v string) string
+		argv     func(rcFile string, script string) []string
+		classify func(desc string) commandType // desc 为去掉命令名后的描述, 如 " is a builtin"
+	}
+)
+
+var (
+	bashProfile = &profile{
+		name:     `bash`,
+		quote:    shellQuote,
+		argv:     bashArgv,
+		classify: matchType,
+	}
+	// fish 没有 keyword/alias 的概念, alias 定义为函数: "is a function with definition", "is a builtin", "is /path"
+	fishProfile = &profile{
+		name:     `fish`,
+		quote:    fishQuote,
+		argv:     fishArgv,
+		classify: fishClassify,
+	}
+	profiles = map[string]*profile{
+		bashProfile.name: bashProfile,
+		fishProfile.name: fishProfile,
+	}
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "fish", Kind: CapabilityBackend, Description: "fish builtin type via fish -c"})
+}
+
+// profile 按 shell 名称选择解析方式, 未知 shell 按 bash 的措辞解析
+func (r *Runner) profile() *profile {
+	if v, ok := profiles[filepath.Base(r.shell)]; ok {
+		return v
+	}
+	return bashProfile
+}
+
+func bashArgv(rcFile string, script string) []string {
+	if rcFile != "" {
+		return []string{`--rcfile`, rcFile, `-ic`, script}
+	}
+	return []string{`-c`, script}
+}
+
+func fishArgv(rcFile string, script string) []string {
+	if rcFile != "" {
+		return []string{`--init-command`, `source ` + fishQuote(rcFile), `-c`, script}
+	}
+	return []string{`-c`, script}
+}
+
+// fishQuote fish 的单引号内仅 \' 与 \\ 需要转义
+func fishQuote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	return `'` + strings.ReplaceAll(v, `'`, `\'`) + `'`
+}
+
+func fishClassify(desc string) commandType {
+	desc = strings.TrimPrefix(desc, ` is `)
+	switch {
+	case strings.HasPrefix(desc, `a function`):
+		return TypeFunction
+	case strings.HasPrefix(desc, `a builtin`):
+		return TypeBuiltin
+	case strings.HasPrefix(desc, `/`):
+		return TypeFile
+	}
+	return TypeUnFound
+}
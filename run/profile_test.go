@@ -0,0 +1,85 @@
+package run
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// fish 4.x type -a 的实际输出
+const (
+	fishFunction = "ll is a function with definition\n" +
+		"# Defined in /usr/share/fish/functions/ll.fish @ line 4\n" +
+		"function ll --wraps=ls --description 'List contents of directory using long format'\n" +
+		"    ls -lh $argv\n" +
+		"end\n"
+	fishBuiltin = "echo is a builtin\necho is /usr/bin/echo\n"
+	fishFile    = "grep is /usr/bin/grep\ngrep is /bin/grep\n"
+)
+
+func TestFishProfile(t *testing.T) {
+	var cases = []struct {
+		cmd     string
+		stdout  string
+		code    int
+		kind    string
+		path    string
+		entries int
+	}{
+		{"ll", fishFunction, 0, "function", "", 1},
+		{"echo", fishBuiltin, 0, "builtin", "", 2},
+		{"grep", fishFile, 0, "file", "/usr/bin/grep", 2},
+		{"nope", "", 1, "unfound", "", 0},
+	}
+	for _, c := range cases {
+		var args []string
+		var runner, _ = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+			args = command.Args
+			return &Capture{Stdout: []byte(c.stdout), Code: c.code}
+		}))
+		var rs = runner.WithShell("/usr/bin/fish").Resolve(c.cmd)
+		if len(args) != 3 || args[1] != "-c" || args[2] != "type '-a' '"+c.cmd+"'" {
+			t.Errorf("%s: args = %q", c.cmd, args)
+		}
+		if rs.Type() != c.kind || rs.Path() != c.path || rs.CandidateCount() != c.entries {
+			t.Errorf("%s: type = %s, path = %q, candidates = %d; want %s, %q, %d",
+				c.cmd, rs.Type(), rs.Path(), rs.CandidateCount(), c.kind, c.path, c.entries)
+		}
+	}
+}
+
+func TestFishOutput(t *testing.T) {
+	var cases = []struct {
+		flag   string
+		cmd    string
+		stdout string
+		want   string
+	}{
+		{"type", "ll", fishFunction, "function\n"},
+		{"type", "echo", fishBuiltin, "builtin\n"},
+		{"type", "grep", fishFile, "file\n"},
+		{"path", "ll", fishFunction, ""},
+		{"path", "echo", fishBuiltin, ""},
+		{"path", "grep", fishFile, "/usr/bin/grep\n"},
+		{"all", "ll", fishFunction, fishFunction},
+	}
+	for _, c := range cases {
+		var runner, read = newTestRunner(t, capturing(c.stdout, "", 0, nil))
+		runner.WithShell("fish").Exec(c.flag, c.cmd)
+		if stdout, _ := read(); string(stdout) != c.want {
+			t.Errorf("%s %s: stdout = %q, want %q", c.flag, c.cmd, stdout, c.want)
+		}
+	}
+}
+
+func TestFishArgv(t *testing.T) {
+	var args = fishArgv(`/tmp/it's\rc`, "type 'ls'")
+	var want = []string{"--init-command", `source '/tmp/it\'s\\rc'`, "-c", "type 'ls'"}
+	if len(args) != len(want) {
+		t.Fatalf("args = %q, want %q", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("args[%d] = %q, want %q", i, args[i], want[i])
+		}
+	}
+}
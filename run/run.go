@@ -7,20 +7,47 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type (
 	Runner struct {
-		bin          string
-		err          *os.File
-		output       *os.File
-		input        *os.File
-		flagHandlers map[string]func(string) (string, error)
+		bin                string
+		err                *os.File
+		output             *os.File
+		input              *os.File
+		shell              string
+		rcFile             string
+		evalCreds          *credentials
+		encodingPolicy     EncodingPolicy
+		wrappers           []Wrapper
+		candidateThreshold int
+		hashCheck          bool
+		resolvers          []Resolver
+		pathOverride       string
+		pathSet            bool
+		commander          Commander
+		cache              resultCache
+		cacheTTL           time.Duration
+		cachePolicyFn      CachePolicy
+		noFunctions        bool
+		flagHandlers       map[string]func(string) *Result
 	}
 
 	Result struct {
-		output string
-		err    error
+		name        string
+		kind        commandType
+		entries     []string
+		path        string // 插件直接给出的路径, 为空时从 entries 中提取
+		alias       string // 插件直接给出的别名定义, 为空时从 entries 中提取
+		code        int
+		creds       *credentials
+		hashChecked bool
+		hashedPath  string
+		freshPath   string
+		raw         string
+		output      string
+		err         error
 	}
 
 	commandType string
@@ -100,6 +127,7 @@ var (
 		"-t": "type",
 		"-a": "all",
 		"-p": "path",
+		"-P": "force-path",
 	}
 )
 
@@ -130,6 +158,62 @@ func (rs *Result) Err() error {
 	return rs.err
 }
 
+// Name 查询的命令名
+func (rs *Result) Name() string {
+	return rs.name
+}
+
+// Type 命令类型, 未解析时为 unfound
+func (rs *Result) Type() string {
+	if rs.kind == "" {
+		return TypeUnFound.String()
+	}
+	return rs.kind.String()
+}
+
+// Entries type -a 返回的每一条解析记录
+func (rs *Result) Entries() []string {
+	return rs.entries
+}
+
+// ExitCode 与原生 type 一致的退出码
+func (rs *Result) ExitCode() int {
+	return rs.code
+}
+
+// Path 首条解析记录为外部命令时的绝对路径
+func (rs *Result) Path() string {
+	if rs.kind != TypeFile {
+		return ``
+	}
+	if rs.path != "" {
+		return rs.path
+	}
+	if len(rs.entries) <= 0 {
+		return ``
+	}
+	return pathOf(rs.name, rs.entries[0])
+}
+
+// Alias 首条解析记录为别名时的别名定义
+func (rs *Result) Alias() string {
+	if rs.kind != TypeAlias {
+		return ``
+	}
+	if rs.alias != "" {
+		return rs.alias
+	}
+	if len(rs.entries) <= 0 {
+		return ``
+	}
+	var v = strings.TrimPrefix(rs.entries[0], rs.name+` is aliased to `)
+	return strings.TrimSuffix(strings.TrimPrefix(v, "`"), `'`)
+}
+
+func (rs *Result) failed() bool {
+	return rs.err != nil || rs.code != 0
+}
+
 // NewRunner 构造执行器
 func NewRunner(err, input, output *os.File) *Runner {
 	var runner = new(Runner)
@@ -140,6 +224,8 @@ func NewRunner(err, input, output *os.File) *Runner {
 
 func (r *Runner) init() {
 	r.bin = GetEnvOr(builtInType, defaultBind)
+	r.commander = execCommander{}
+	r.cacheTTL = defaultCacheTTL
 	r.flagHandlers = r.createHandlers()
 }
 
@@ -158,81 +244,118 @@ func (r *Runner) Bind(bin string) *Runner {
 			return r
 		}
 		r.bin = p
+		r.cache.clear()
 	}
 	return r
 }
 
-func (r *Runner) createHandlers() map[string]func(string) (string, error) {
-	return map[string]func(string) (string, error){
-		"type": r.parseType,
-		"all":  r.parseAll,
-		"path": r.parsePath,
+// WithCommander 替换子进程执行器
+func (r *Runner) WithCommander(commander Commander) *Runner {
+	if commander == nil {
+		return r
 	}
+	r.commander = commander
+	r.cache.clear()
+	return r
+}
+
+func (r *Runner) createHandlers() map[string]func(string) *Result {
+	return map[string]func(string) *Result{
+		"type":       r.parseType,
+		"all":        r.parseAll,
+		"path":       r.parsePath,
+		"force-path": r.parseForcePath,
+	}
+}
+
+// derive 解析结果的副本, 供各选项填充输出而不修改缓存中的结果
+func (r *Runner) derive(cmd string) *Result {
+	var rs = *r.Resolve(cmd)
+	return &rs
 }
 
-func (r *Runner) parseType(cmd string) (string, error) {
-	var (
-		args       = []string{`-a`, cmd}
-		command    = r.command(args)
-		bytes, err = command.Output()
-	)
-	if err != nil {
-		return TypeUnFound.String(), nil
+func (r *Runner) parseType(cmd string) *Result {
+	var rs = r.derive(cmd)
+	if errors.Is(rs.err, ErrInvalidEncoding) {
+		return rs
 	}
-	if len(bytes) <= 0 {
-		return TypeUnFound.String(), nil
+	if rs.failed() || len(rs.entries) <= 0 {
+		rs.output = TypeUnFound.String()
+		return rs
 	}
-	var lines = strings.Split(string(bytes), "\n")
-	return r.getType(lines[0]).String(), nil
+	rs.output = rs.Type()
+	return rs
 }
 
 func (r *Runner) command(args []string) *exec.Cmd {
+	if r.shell != "" {
+		return r.shellCommand(args)
+	}
 	var command = exec.Command(r.bin, args...)
-	command.Env = os.Environ()
+	command.Env = r.environ()
 	return command
 }
 
-func (r *Runner) parseAll(cmd string) (string, error) {
-	var (
-		args       = []string{`-a`, cmd}
-		command    = r.command(args)
-		bytes, err = command.Output()
-	)
-	if err != nil {
-		return cmd + ` not found`, nil
-	}
-	return string(bytes), nil
-}
-
-func (r *Runner) parsePath(cmd string) (string, error) {
-	var (
-		args       = []string{`-a`, cmd}
-		command    = r.command(args)
-		bytes, err = command.Output()
-	)
-	if err != nil {
-		return cmd + ` not found`, nil
-	}
-	var (
-		lines = strings.Split(string(bytes), "\n")
-		ty    = r.getType(lines[0])
-	)
-	switch ty {
-	case TypeFile:
-		var (
-			index = 1
-			argc  = len(lines)
-		)
-		if argc < 2 {
-			index = 0
-		}
-		var paths = strings.Split(lines[index], `is`)
-		return strings.TrimSpace(paths[1]), nil
+func (r *Runner) capture(args []string) *Capture {
+	var commander = r.commander
+	if commander == nil {
+		commander = execCommander{}
 	}
-	return ``, nil
+	var capture = commander.Run(r.command(args))
+	if r.rcFile != "" {
+		capture.Stderr = stripInteractiveNoise(capture.Stderr)
+	}
+	return capture
+}
+
+func (r *Runner) parseAll(cmd string) *Result {
+	var rs = r.derive(cmd)
+	if errors.Is(rs.err, ErrInvalidEncoding) {
+		return rs
+	}
+	if rs.failed() {
+		rs.output = cmd + ` not found`
+		return rs
+	}
+	rs.output = rs.raw
+	return rs
+}
+
+func (r *Runner) parsePath(cmd string) *Result {
+	var rs = r.derive(cmd)
+	if errors.Is(rs.err, ErrInvalidEncoding) {
+		return rs
+	}
+	if rs.failed() {
+		rs.output = cmd + ` not found`
+		return rs
+	}
+	rs.output = rs.Path()
+	return rs
+}
+
+// parseForcePath 同 type -P: 忽略别名、函数与内建命令, 只在 PATH 中查找
+func (r *Runner) parseForcePath(cmd string) *Result {
+	var rs = NewResult()
+	rs.name = cmd
+	rs.creds = r.evalCreds
+	rs.output = LookPath(cmd, r.pathEnv())
+	if rs.output == "" {
+		rs.kind = TypeUnFound
+		rs.code = 1
+		return rs
+	}
+	rs.kind = TypeFile
+	rs.entries = []string{cmd + ` is ` + rs.output}
+	rs.raw = rs.entries[0] + "\n"
+	return rs
 }
 
 func (r *Runner) getType(info string) commandType {
+	return matchType(info)
+}
+
+func matchType(info string) commandType {
 	for _, v := range types {
 		if v.Match(info) {
 			return v
@@ -258,21 +381,45 @@ func (r *Runner) Exec(flag string, cmd string) *Result {
 	if cmd == "" {
 		return rs
 	}
+	rs = r.lookup(flag, cmd)
+	r.emit(rs)
+	return rs
+}
+
+// lookup 解析并记录错误, 不输出
+func (r *Runner) lookup(flag string, cmd string) *Result {
+	var rs = r.dispatch(flag, cmd)
+	if rs.err != nil {
+		r.errLog("cmd err:", rs.err)
+	}
+	return rs
+}
+
+// dispatch 按选项解析命令, 不输出
+func (r *Runner) dispatch(flag string, cmd string) *Result {
 	if strings.HasPrefix(flag, "-") {
 		flag = r.short2Long(flag)
 	}
 	if fn, ok := r.flagHandlers[flag]; ok {
-		rs.output, rs.err = fn(cmd)
-	} else {
-		rs.err = errors.New(flag + `:flag undefined `)
+		return fn(cmd)
 	}
+	var rs = NewResult()
+	rs.name = cmd
+	rs.err = errors.New(flag + `:flag undefined `)
+	return rs
+}
+
+// emit 输出选项结果, 与 type 一致无结果时(如 -p 内建命令)不输出空行
+func (r *Runner) emit(rs *Result) {
 	var out = rs.Get()
+	if out == "" {
+		return
+	}
 	if strings.HasSuffix(out, "\n") {
 		r.print(out)
 	} else {
 		r.println(out)
 	}
-	return rs
 }
 
 func (r *Runner) print(args ...interface{}) int {
@@ -315,6 +462,9 @@ func (r *Runner) errLog(args ...interface{}) int {
 }
 
 func (r *Runner) short2Long(flag string) string {
+	if v, ok := shortFlagMap[flag]; ok {
+		return v
+	}
 	if v, ok := shortFlagMap[strings.ToLower(flag)]; ok {
 		return v
 	}
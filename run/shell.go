@@ -0,0 +1,81 @@
+package run
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+func init() {
+	RegisterCapability(Capability{Name: "shell", Kind: CapabilityBackend, Description: "shell builtin type via <shell> -c"})
+	RegisterCapability(Capability{Name: "rcfile", Kind: CapabilityFeature, Description: "aliases from an rc file via an interactive shell"})
+}
+
+const (
+	defaultShell = `bash`
+)
+
+var (
+	// 无终端时交互式 bash 固定输出的作业控制提示, 不属于 type 的输出
+	interactiveNoise = [][]byte{
+		[]byte(`cannot set terminal process group`),
+		[]byte(`no job control in this shell`),
+	}
+)
+
+// WithShell 改由 shell 内建 type 解析, 按 shell 名称选择输出措辞(bash、fish), 空字符串恢复使用 type 可执行文件
+func (r *Runner) WithShell(shell string) *Runner {
+	r.shell = shell
+	r.cache.clear()
+	return r
+}
+
+// WithRcFile 加载 rc 文件后再执行 type, 使其中定义的别名可见
+// 未指定 shell 时使用 bash: bash --rcfile path -ic 'type -a cmd', fish 则通过 --init-command 加载
+func (r *Runner) WithRcFile(path string) *Runner {
+	r.rcFile = path
+	r.cache.clear()
+	if path != "" && r.shell == "" {
+		r.shell = defaultShell
+	}
+	return r
+}
+
+func (r *Runner) shellCommand(args []string) *exec.Cmd {
+	var (
+		profile = r.profile()
+		script  = `type`
+	)
+	for _, v := range args {
+		script += ` ` + profile.quote(v)
+	}
+	var command = exec.Command(r.shell, profile.argv(r.rcFile, script)...)
+	command.Env = r.environ()
+	return command
+}
+
+// shellQuote 单引号转义, 防止命令名被 shell 展开
+func shellQuote(v string) string {
+	return `'` + strings.ReplaceAll(v, `'`, `'\''`) + `'`
+}
+
+// stripInteractiveNoise 去除交互式 shell 在无终端时输出的作业控制提示
+func stripInteractiveNoise(stderr []byte) []byte {
+	if len(stderr) <= 0 {
+		return stderr
+	}
+	var lines [][]byte
+	for _, line := range bytes.SplitAfter(stderr, []byte("\n")) {
+		var noise = false
+		for _, v := range interactiveNoise {
+			if bytes.Contains(line, v) {
+				noise = true
+				break
+			}
+		}
+		if !noise {
+			lines = append(lines, line)
+		}
+	}
+	return bytes.Join(lines, nil)
+}
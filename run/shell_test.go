@@ -0,0 +1,63 @@
+package run
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithRcFileResolvesAlias(t *testing.T) {
+	var bash, err = exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+	var rc = filepath.Join(t.TempDir(), "rc")
+	if err = os.WriteFile(rc, []byte("alias gotype-ll='ls -l'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var runner, _ = newTestRunner(t, nil)
+	runner.WithShell(bash).WithRcFile(rc)
+	var rs = runner.Resolve("gotype-ll")
+	if rs.Type() != TypeAlias.String() {
+		t.Fatalf("type = %s, want alias (entries %q, err %v)", rs.Type(), rs.Entries(), rs.Err())
+	}
+	if rs.Alias() != "ls -l" {
+		t.Errorf("alias = %q, want %q", rs.Alias(), "ls -l")
+	}
+}
+
+func TestWithRcFileInvocation(t *testing.T) {
+	var args []string
+	var runner, _ = newTestRunner(t, commanderFunc(func(command *exec.Cmd) *Capture {
+		args = command.Args
+		return &Capture{Stdout: []byte("x is aliased to `y'\n")}
+	}))
+	runner.WithRcFile("/tmp/rc").Resolve("it's")
+	var want = []string{"bash", "--rcfile", "/tmp/rc", "-ic", `type '-a' 'it'\''s'`}
+	if len(args) != len(want) {
+		t.Fatalf("args = %q, want %q", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("args[%d] = %q, want %q", i, args[i], want[i])
+		}
+	}
+}
+
+func TestStripInteractiveNoise(t *testing.T) {
+	var cases = []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"bash: cannot set terminal process group (-1): Inappropriate ioctl for device\nbash: no job control in this shell\n", ""},
+		{"bash: no job control in this shell\nbash: type: nope: not found\n", "bash: type: nope: not found\n"},
+		{"bash: type: nope: not found", "bash: type: nope: not found"},
+	}
+	for _, c := range cases {
+		if got := string(stripInteractiveNoise([]byte(c.in))); got != c.want {
+			t.Errorf("stripInteractiveNoise(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package run
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// Wrapper 提权/环境包装命令, ArgFlags 为需要额外参数的选项
+	// SplitFlags 的参数本身是一段命令行(如 env -S), 拆分后继续解析
+	Wrapper struct {
+		Name       string
+		ArgFlags   []string
+		SplitFlags []string
+	}
+)
+
+var (
+	defaultWrappers = []Wrapper{
+		{Name: `sudo`, ArgFlags: []string{
+			`-u`, `-g`, `-h`, `-p`, `-C`, `-D`, `-r`, `-t`, `-U`, `-T`,
+			`--user`, `--group`, `--host`, `--prompt`, `--close-from`, `--chdir`, `--role`, `--type`, `--other-user`, `--command-timeout`,
+		}},
+		{Name: `doas`, ArgFlags: []string{`-u`, `-C`}},
+		{Name: `env`, ArgFlags: []string{`-u`, `-C`, `--unset`, `--chdir`}, SplitFlags: []string{`-S`, `--split-string`}},
+	}
+)
+
+// DefaultWrappers 默认识别的包装命令
+func DefaultWrappers() []Wrapper {
+	return append([]Wrapper(nil), defaultWrappers...)
+}
+
+// WithWrappers 替换识别的包装命令列表
+func (r *Runner) WithWrappers(wrappers ...Wrapper) *Runner {
+	r.wrappers = wrappers
+	return r
+}
+
+// StripWrapper 去除命令行开头的包装命令(可嵌套, 如 sudo env A=1 cmd), 返回真正的命令及被去除的包装命令
+func (r *Runner) StripWrapper(line string) (string, []string) {
+	var (
+		wrappers = r.wrappers
+		fields   = splitFields(line)
+		stripped []string
+	)
+	if wrappers == nil {
+		wrappers = defaultWrappers
+	}
+	for len(fields) > 0 {
+		var wrapper, ok = findWrapper(wrappers, filepath.Base(fields[0]))
+		if !ok {
+			break
+		}
+		stripped = append(stripped, wrapper.Name)
+		fields = skipWrapperArgs(wrapper, fields[1:])
+	}
+	// 只有包装命令本身时解析包装命令
+	if len(fields) <= 0 {
+		if len(stripped) <= 0 {
+			return ``, nil
+		}
+		return stripped[len(stripped)-1], stripped[:len(stripped)-1]
+	}
+	return fields[0], stripped
+}
+
+func findWrapper(wrappers []Wrapper, name string) (Wrapper, bool) {
+	for _, v := range wrappers {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Wrapper{}, false
+}
+
+// skipWrapperArgs 跳过包装命令的选项及 NAME=VALUE 环境变量赋值
+func skipWrapperArgs(wrapper Wrapper, fields []string) []string {
+	for len(fields) > 0 {
+		var v = fields[0]
+		switch {
+		case v == `--`:
+			return fields[1:]
+		case strings.HasPrefix(v, `--`):
+			fields = skipLongFlag(wrapper, v, fields[1:])
+		case strings.HasPrefix(v, `-`):
+			fields = skipShortFlags(wrapper, v, fields[1:])
+		case strings.Contains(v, `=`) && !strings.HasPrefix(v, `=`):
+			fields = fields[1:]
+		default:
+			return fields
+		}
+	}
+	return fields
+}
+
+// skipLongFlag 跳过长选项及其参数, --split-string ARG 与 --split-string=ARG 的命令行拆分后放回待解析字段
+func skipLongFlag(wrapper Wrapper, v string, next []string) []string {
+	var (
+		parts    = strings.SplitN(v, `=`, 2)
+		name     = parts[0]
+		attached = len(parts) == 2
+		arg      string
+	)
+	if attached {
+		arg = parts[1]
+	}
+	switch {
+	case hasFlag(wrapper.SplitFlags, name) && attached:
+		return append(splitFields(arg), next...)
+	case hasFlag(wrapper.SplitFlags, name) && len(next) > 0:
+		return append(splitFields(next[0]), next[1:]...)
+	case hasFlag(wrapper.ArgFlags, name) && !attached && len(next) > 0:
+		return next[1:]
+	}
+	return next
+}
+
+// skipShortFlags 跳过短选项, 支持合并写法(如 -Eu root、-nuroot):
+// 首个需要参数的字母之后的部分即为其参数, 该字母位于末尾时参数为下一个字段
+func skipShortFlags(wrapper Wrapper, group string, next []string) []string {
+	for i := 1; i < len(group); i++ {
+		var flag, arg = `-` + group[i:i+1], group[i+1:]
+		switch {
+		case hasFlag(wrapper.SplitFlags, flag) && arg != "":
+			return append(splitFields(arg), next...)
+		case hasFlag(wrapper.SplitFlags, flag) && len(next) > 0:
+			return append(splitFields(next[0]), next[1:]...)
+		case hasFlag(wrapper.ArgFlags, flag) && arg == "" && len(next) > 0:
+			return next[1:]
+		case hasFlag(wrapper.ArgFlags, flag) || hasFlag(wrapper.SplitFlags, flag):
+			return next
+		}
+	}
+	return next
+}
+
+func hasFlag(flags []string, flag string) bool {
+	for _, v := range flags {
+		if v == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFields 按空白切分命令行, 单双引号内的空白不切分, 引号本身去除
+func splitFields(line string) []string {
+	var (
+		fields  []string
+		field   strings.Builder
+		quote   rune
+		inField bool
+	)
+	for _, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			field.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			inField = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(c)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
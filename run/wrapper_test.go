@@ -0,0 +1,57 @@
+package run
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripWrapper(t *testing.T) {
+	var cases = []struct {
+		line     string
+		cmd      string
+		wrappers string
+	}{
+		{"ls -l", "ls", ""},
+		{"sudo ls", "ls", "sudo"},
+		{"sudo -u root -E ls", "ls", "sudo"},
+		{"sudo -uroot ls", "ls", "sudo"},
+		{"sudo -Eu root ls", "ls", "sudo"},
+		{"sudo -Euroot ls", "ls", "sudo"},
+		{"sudo -nEH ls", "ls", "sudo"},
+		{"doas -nu root ls", "ls", "doas"},
+		{"env -iu HOME ls", "ls", "env"},
+		{"env -iS 'A=1 ls'", "ls", "env"},
+		{"sudo --user root ls", "ls", "sudo"},
+		{"sudo --user=root ls", "ls", "sudo"},
+		{"/usr/bin/sudo -- ls", "ls", "sudo"},
+		{"doas -u www nginx -t", "nginx", "doas"},
+		{"env A=1 B=2 make", "make", "env"},
+		{"env -i -u HOME -C /tmp ls", "ls", "env"},
+		{"env --unset HOME --chdir /tmp ls", "ls", "env"},
+		{"env -S 'A=1 python3 -u' x.py", "python3", "env"},
+		{`env -S"perl -w"`, "perl", "env"},
+		{"env --split-string='A=1 node'", "node", "env"},
+		{"env -S ls", "ls", "env"},
+		{"sudo env A=1 doas -u root ls", "ls", "sudo env doas"},
+		{"sudo", "sudo", ""},
+		{"sudo env", "env", "sudo"},
+		{"", "", ""},
+	}
+	var runner = NewRunner(nil, nil, nil)
+	for _, c := range cases {
+		var cmd, wrappers = runner.StripWrapper(c.line)
+		if cmd != c.cmd || strings.Join(wrappers, " ") != c.wrappers {
+			t.Errorf("StripWrapper(%q) = %q, %q; want %q, %q", c.line, cmd, wrappers, c.cmd, c.wrappers)
+		}
+	}
+}
+
+func TestStripCustomWrapper(t *testing.T) {
+	var runner = NewRunner(nil, nil, nil).WithWrappers(Wrapper{Name: "nice", ArgFlags: []string{"-n"}})
+	if cmd, _ := runner.StripWrapper("nice -n 10 make"); cmd != "make" {
+		t.Errorf("cmd = %q, want make", cmd)
+	}
+	if cmd, _ := runner.StripWrapper("sudo make"); cmd != "sudo" {
+		t.Errorf("cmd = %q, want sudo when not configured", cmd)
+	}
+}